@@ -0,0 +1,7 @@
+data "casaos_app" "jellyfin" {
+  name = "jellyfin"
+}
+
+output "jellyfin_url" {
+  value = data.casaos_app.jellyfin.web_ui_url
+}
@@ -0,0 +1,11 @@
+data "casaos_app_store_catalog" "media" {
+  category     = "Media"
+  architecture = "arm64"
+  search       = "music"
+}
+
+output "media_apps" {
+  value = {
+    for app in data.casaos_app_store_catalog.media.apps : app.store_app_id => app.version
+  }
+}
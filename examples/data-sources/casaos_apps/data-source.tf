@@ -0,0 +1,9 @@
+data "casaos_apps" "all" {}
+
+# Web UI URLs of every running app, e.g. to configure a reverse proxy.
+output "running_apps" {
+  value = {
+    for app in data.casaos_apps.all.apps : app.name => app.web_ui_url
+    if app.status == "running" && app.web_ui_url != ""
+  }
+}
@@ -0,0 +1,13 @@
+data "casaos_directory_listing" "media" {
+  path = "/DATA/Media"
+}
+
+data "casaos_directory_listing" "movies" {
+  path      = "/DATA/Media/Movies"
+  pattern   = "*.mkv"
+  max_depth = 3
+}
+
+output "media_folders" {
+  value = [for entry in data.casaos_directory_listing.media.entries : entry.path if entry.is_dir]
+}
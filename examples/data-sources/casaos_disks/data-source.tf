@@ -0,0 +1,15 @@
+data "casaos_disks" "all" {}
+
+locals {
+  data_disk = one([
+    for disk in data.casaos_disks.all.disks : disk if disk.serial == "WD-WX12D3456789"
+  ])
+}
+
+# Fail the plan when the expected data disk is missing or failing.
+check "data_disk" {
+  assert {
+    condition     = local.data_disk != null && local.data_disk.healthy
+    error_message = "The data disk is not attached or not healthy."
+  }
+}
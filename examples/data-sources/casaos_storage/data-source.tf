@@ -0,0 +1,18 @@
+data "casaos_storage" "all" {}
+
+locals {
+  media = one([
+    for storage in data.casaos_storage.all.storages : storage if storage.mount_point == "/media/Storage1"
+  ])
+}
+
+resource "casaos_app_store_app" "jellyfin" {
+  store_app_id = "Jellyfin"
+
+  lifecycle {
+    precondition {
+      condition     = local.media.available > 100 * 1024 * 1024 * 1024
+      error_message = "Less than 100 GiB left on ${local.media.mount_point}."
+    }
+  }
+}
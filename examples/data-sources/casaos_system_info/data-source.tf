@@ -0,0 +1,14 @@
+data "casaos_system_info" "this" {}
+
+locals {
+  # Leave a quarter of the memory of the device to CasaOS and other apps.
+  app_memory_limit = floor(data.casaos_system_info.this.memory_total * 0.75)
+
+  lan_addresses = flatten([
+    for iface in data.casaos_system_info.this.network_interfaces : iface.ips if iface.name == "eth0"
+  ])
+}
+
+output "architecture" {
+  value = data.casaos_system_info.this.architecture
+}
@@ -1,3 +1,10 @@
-provider "scaffolding" {
-  # example configuration here
+provider "casaos" {
+  endpoint = "http://casaos.local"
+  username = "admin"
+  password = var.casaos_password
+}
+
+variable "casaos_password" {
+  type      = string
+  sensitive = true
 }
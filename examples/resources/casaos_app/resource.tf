@@ -0,0 +1,20 @@
+resource "casaos_app" "whoami" {
+  compose = <<-EOT
+    name: whoami
+    services:
+      whoami:
+        image: traefik/whoami:v1.10
+        restart: unless-stopped
+        ports:
+          - target: 80
+            published: "8080"
+            protocol: tcp
+    x-casaos:
+      main: whoami
+      port_map: "8080"
+      scheme: http
+      icon: https://cdn.jsdelivr.net/gh/IceWhaleTech/CasaOS-AppStore@main/Apps/Whoami/icon.png
+      title:
+        en_us: Whoami
+  EOT
+}
@@ -0,0 +1,2 @@
+# The app grid of the device is imported with the fixed ID app_grid.
+terraform import casaos_app_grid.this app_grid
@@ -0,0 +1,11 @@
+# Put the media apps first and hide the tools nobody uses from the dashboard.
+resource "casaos_app_grid" "this" {
+  order = [
+    casaos_app_store_app.jellyfin.name,
+    "qbittorrent",
+  ]
+
+  hidden = [
+    "portainer",
+  ]
+}
@@ -0,0 +1,2 @@
+# Links can be imported by their ID, the name of their tile on the app grid.
+terraform import casaos_app_link.router link-3f2a9c1e5b7d
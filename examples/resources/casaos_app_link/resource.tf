@@ -0,0 +1,19 @@
+resource "casaos_app_link" "router" {
+  title = "Router"
+  url   = "http://192.168.1.1"
+  icon  = "https://cdn.jsdelivr.net/gh/walkxcode/dashboard-icons/png/openwrt.png"
+}
+
+resource "casaos_app_link" "printer" {
+  title           = "Printer"
+  url             = "http://printer.lan"
+  open_in_new_tab = false
+}
+
+# Show the links before the apps.
+resource "casaos_app_grid" "this" {
+  order = [
+    casaos_app_link.router.id,
+    casaos_app_link.printer.id,
+  ]
+}
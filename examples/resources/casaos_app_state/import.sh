@@ -0,0 +1,2 @@
+# App states can be imported by the app name.
+terraform import casaos_app_state.whoami whoami
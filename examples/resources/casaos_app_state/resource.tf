@@ -0,0 +1,9 @@
+resource "casaos_app_state" "whoami" {
+  name  = casaos_app.whoami.name
+  state = "running"
+
+  # Restart the app whenever its configuration file changes.
+  restart_triggers = {
+    config = filesha256("${path.module}/whoami.yaml")
+  }
+}
@@ -0,0 +1,2 @@
+# App stores can be imported by their URL.
+terraform import casaos_app_store.linuxserver https://casaos-appstore.paodayag.dev/linuxserver.zip
@@ -0,0 +1,3 @@
+resource "casaos_app_store" "linuxserver" {
+  url = "https://casaos-appstore.paodayag.dev/linuxserver.zip"
+}
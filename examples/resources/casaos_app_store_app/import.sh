@@ -0,0 +1,2 @@
+# Apps can be imported by their name, the top-level name of the catalog compose file.
+terraform import casaos_app_store_app.jellyfin jellyfin
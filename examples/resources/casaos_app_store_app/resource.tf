@@ -0,0 +1,29 @@
+resource "casaos_app_store_app" "jellyfin" {
+  store_app_id = "Jellyfin"
+
+  ports = {
+    "8096" = "8097"
+  }
+
+  volumes = {
+    "/media" = "/DATA/Media"
+  }
+
+  environment = {
+    TZ = "Europe/Berlin"
+  }
+
+  web_ui_port = "8097"
+}
+
+resource "casaos_app_store_app" "immich" {
+  store_app_id = "Immich"
+
+  # Only finish once the web UI answers, the first start runs migrations.
+  wait_for_web_ui = true
+
+  timeouts {
+    create = "45m"
+    update = "45m"
+  }
+}
@@ -0,0 +1,2 @@
+# The widget settings of the device are imported with the fixed ID dashboard_widgets.
+terraform import casaos_dashboard_widgets.this dashboard_widgets
@@ -0,0 +1,8 @@
+# Show the system status and the storage usage, in this order, and hide the
+# other widgets.
+resource "casaos_dashboard_widgets" "this" {
+  enabled = [
+    "system_status",
+    "storage",
+  ]
+}
@@ -0,0 +1,3 @@
+# Files can be imported by their path. The next apply uploads the configured
+# content again.
+terraform import casaos_file.nginx_conf /DATA/AppData/nginx/nginx.conf
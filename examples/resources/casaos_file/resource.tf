@@ -0,0 +1,23 @@
+resource "casaos_folder" "nginx" {
+  path = "/DATA/AppData/nginx"
+}
+
+resource "casaos_file" "nginx_conf" {
+  path    = "${casaos_folder.nginx.path}/nginx.conf"
+  content = <<-EOT
+    server {
+      listen 80;
+      root /usr/share/nginx/html;
+    }
+  EOT
+}
+
+resource "casaos_file" "favicon" {
+  path           = "${casaos_folder.nginx.path}/favicon.ico"
+  content_base64 = filebase64("${path.module}/favicon.ico")
+}
+
+resource "casaos_file" "index" {
+  path   = "${casaos_folder.nginx.path}/index.html"
+  source = "${path.module}/index.html"
+}
@@ -0,0 +1,2 @@
+# Folders can be imported by their path.
+terraform import casaos_folder.nginx /DATA/AppData/nginx
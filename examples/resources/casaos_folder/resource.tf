@@ -0,0 +1,3 @@
+resource "casaos_folder" "nginx" {
+  path = "/DATA/AppData/nginx"
+}
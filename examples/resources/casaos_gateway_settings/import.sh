@@ -0,0 +1,2 @@
+# The gateway settings of the device are imported with the fixed ID gateway_settings.
+terraform import casaos_gateway_settings.this gateway_settings
@@ -0,0 +1,14 @@
+# Free port 80 for a reverse proxy. Afterwards, set the endpoint of the
+# provider to http://casaos.local:8080.
+resource "casaos_gateway_settings" "this" {
+  port = 8080
+}
+
+# Resources that depend on the gateway settings reach the device on the new
+# port in the same apply.
+resource "casaos_app_link" "proxy" {
+  title = "Reverse Proxy"
+  url   = "http://casaos.local:81"
+
+  depends_on = [casaos_gateway_settings.this]
+}
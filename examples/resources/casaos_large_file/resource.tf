@@ -0,0 +1,12 @@
+resource "casaos_large_file" "debian_iso" {
+  path   = "/DATA/Downloads/debian-12.iso"
+  source = "${path.module}/debian-12.iso"
+
+  # Smaller chunks for a device on a slow link.
+  chunk_size = 4194304
+
+  timeouts {
+    create = "2h"
+    update = "2h"
+  }
+}
@@ -0,0 +1,2 @@
+# Merged storages can be imported by their ID.
+terraform import casaos_merged_storage.media 1
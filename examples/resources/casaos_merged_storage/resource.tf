@@ -0,0 +1,18 @@
+resource "casaos_storage" "disk2" {
+  disk         = "/dev/sdc"
+  name         = "Storage2"
+  allow_format = true
+}
+
+# Pool the existing storage and the new disk into one folder for media apps.
+resource "casaos_merged_storage" "media" {
+  mount_point = "/media/Pool"
+  members = [
+    "/media/Storage1",
+    casaos_storage.disk2.mount_point,
+  ]
+}
+
+output "pool_free_bytes" {
+  value = casaos_merged_storage.media.available
+}
@@ -0,0 +1,10 @@
+variable "nas_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "casaos_samba_connection" "nas" {
+  host     = "192.168.1.20"
+  username = "casaos"
+  password = var.nas_password
+}
@@ -0,0 +1,2 @@
+# SMB shares can be imported by their name.
+terraform import casaos_samba_share.media Media
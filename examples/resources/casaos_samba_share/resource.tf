@@ -0,0 +1,10 @@
+resource "casaos_samba_share" "media" {
+  name = "Media"
+  path = "/DATA/Media"
+}
+
+resource "casaos_samba_share" "public" {
+  name      = "Public"
+  path      = "/DATA/Downloads"
+  anonymous = true
+}
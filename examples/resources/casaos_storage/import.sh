@@ -0,0 +1,2 @@
+# Storages can be imported by their mount point.
+terraform import casaos_storage.data /media/Data
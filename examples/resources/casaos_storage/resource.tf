@@ -0,0 +1,21 @@
+data "casaos_disks" "all" {}
+
+locals {
+  # Pick the data disk by serial number, device paths can change between boots.
+  data_disk = one([
+    for disk in data.casaos_disks.all.disks : disk.path if disk.serial == "ZFL1ABCD"
+  ])
+}
+
+resource "casaos_storage" "data" {
+  disk       = local.data_disk
+  name       = "Data"
+  filesystem = "ext4"
+
+  # Creating the storage erases everything on the disk.
+  allow_format = true
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
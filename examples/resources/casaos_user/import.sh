@@ -0,0 +1,3 @@
+# The owner account can be imported by its username. The next apply sets the
+# configured password.
+terraform import casaos_user.owner admin
@@ -0,0 +1,20 @@
+variable "casaos_password" {
+  type      = string
+  sensitive = true
+}
+
+# On a freshly installed device the provider skips logging in, and this
+# resource registers the owner it logs in as from then on.
+provider "casaos" {
+  endpoint = "http://casaos.local"
+  username = "admin"
+  password = var.casaos_password
+}
+
+# To change the password later, change it in this resource and apply before
+# changing it in the provider.
+resource "casaos_user" "owner" {
+  username = "admin"
+  password = var.casaos_password
+  avatar   = "https://example.com/avatar.png"
+}
@@ -0,0 +1,2 @@
+# Custom data can be imported by its key.
+terraform import casaos_user_custom_data.wallpaper wallpaper
@@ -0,0 +1,12 @@
+resource "casaos_user_custom_data" "search_engine" {
+  key = "search_engine"
+  value = jsonencode({
+    name = "DuckDuckGo"
+    url  = "https://duckduckgo.com/?q="
+  })
+}
+
+resource "casaos_user_custom_data" "wallpaper" {
+  key   = "wallpaper"
+  value = file("${path.module}/wallpaper.json")
+}
@@ -0,0 +1,698 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OfficialAppStoreURL is the URL of the app store every server starts with.
+const OfficialAppStoreURL = "https://github.com/IceWhaleTech/CasaOS-AppStore/archive/refs/heads/main.zip"
+
+// app is an installed compose app.
+type app struct {
+	name    string
+	compose string
+	project map[string]interface{}
+	status  string
+	logs    []string
+}
+
+// appStore is a registered app store source with its catalog of compose
+// files, keyed by store app ID. Like CasaOS, the server numbers stores by
+// their position, so removing a store renumbers the ones after it.
+type appStore struct {
+	url     string
+	name    string
+	catalog map[string]string
+}
+
+func (s *Server) seedAppStores() {
+	s.stores = []*appStore{
+		{
+			url:  OfficialAppStoreURL,
+			name: "main",
+			catalog: map[string]string{
+				"Jellyfin":  jellyfinCompose,
+				"Syncthing": syncthingCompose,
+			},
+		},
+	}
+}
+
+// ReplaceInCatalog replaces old with replacement in the compose file of storeAppID in
+// every store, as a new release of the app in the catalog does.
+func (s *Server) ReplaceInCatalog(storeAppID, old, replacement string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, store := range s.stores {
+		if compose, ok := store.catalog[storeAppID]; ok {
+			store.catalog[storeAppID] = strings.ReplaceAll(compose, old, replacement)
+		}
+	}
+}
+
+func (s *Server) registerAppManagementRoutes() {
+	s.handle(http.MethodGet, "/v2/app_management/compose", s.listApps)
+	s.handle(http.MethodPost, "/v2/app_management/compose", s.installApp)
+	s.handle(http.MethodGet, "/v2/app_management/compose/{name}", s.getAppCompose)
+	s.handle(http.MethodPut, "/v2/app_management/compose/{name}", s.updateApp)
+	s.handle(http.MethodDelete, "/v2/app_management/compose/{name}", s.uninstallApp)
+	s.handle(http.MethodGet, "/v2/app_management/compose/{name}/status", s.getAppStatus)
+	s.handle(http.MethodPut, "/v2/app_management/compose/{name}/status", s.setAppStatus)
+	s.handle(http.MethodGet, "/v2/app_management/compose/{name}/containers", s.getAppContainers)
+	s.handle(http.MethodGet, "/v2/app_management/compose/{name}/logs", s.getAppLogs)
+	s.handle(http.MethodGet, "/v2/app_management/apps/{id}/compose", s.getCatalogAppCompose)
+	s.handle(http.MethodGet, "/v2/app_management/appstore", s.listAppStores)
+	s.handle(http.MethodPost, "/v2/app_management/appstore", s.registerAppStore)
+	s.handle(http.MethodDelete, "/v2/app_management/appstore/{id}", s.unregisterAppStore)
+	s.handle(http.MethodGet, "/v2/app_management/appstore/{id}/apps", s.listAppStoreCatalog)
+}
+
+// AppStatus returns the status of an installed app, or "" when there is no
+// such app.
+func (s *Server) AppStatus(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.apps[name]; ok {
+		return a.status
+	}
+
+	return ""
+}
+
+// SetAppStatus changes the status of an installed app behind the provider's
+// back, as if it had been started or stopped from the dashboard.
+func (s *Server) SetAppStatus(name, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.apps[name]; ok {
+		a.status = status
+		a.logs = append(a.logs, fmt.Sprintf("status changed to %s from the dashboard", status))
+	}
+}
+
+// AppStoreRegistered reports whether an app store is registered under
+// storeURL.
+func (s *Server) AppStoreRegistered(storeURL string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, store := range s.stores {
+		if store.url == storeURL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveAppStore unregisters the app store at storeURL behind the provider's
+// back, which renumbers the stores registered after it.
+func (s *Server) RemoveAppStore(storeURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, store := range s.stores {
+		if store.url == storeURL {
+			s.stores = append(s.stores[:i], s.stores[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apps := map[string]interface{}{}
+
+	for name, a := range s.apps {
+		apps[name] = map[string]interface{}{
+			"status":           a.status,
+			"store_info":       storeInfo(a.project),
+			"compose":          projectJSON(a.project),
+			"update_available": false,
+			"is_uncontrolled":  false,
+		}
+	}
+
+	writeData(w, r, apps)
+}
+
+func (s *Server) installApp(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	compose, project, ok := readCompose(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := project["name"].(string)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.apps[name]; exists {
+		writeError(w, r, http.StatusConflict, fmt.Sprintf("app %q is already installed", name))
+		return
+	}
+
+	s.apps[name] = &app{
+		name:    name,
+		compose: compose,
+		project: project,
+		status:  "running",
+		logs:    pullLogs(project),
+	}
+
+	s.mkdirAll(path.Join("/DATA/AppData", name))
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) getAppCompose(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[params["name"]]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found", params["name"]))
+		return
+	}
+
+	writeYAML(w, a.compose)
+}
+
+func (s *Server) updateApp(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	compose, project, ok := readCompose(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[params["name"]]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found", params["name"]))
+		return
+	}
+
+	if name, _ := project["name"].(string); name != a.name {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("compose name %q does not match app %q", name, a.name))
+		return
+	}
+
+	a.compose = compose
+	a.project = project
+	a.status = "running"
+	a.logs = append(a.logs, pullLogs(project)...)
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) uninstallApp(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := params["name"]
+
+	if _, ok := s.apps[name]; !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found", name))
+		return
+	}
+
+	delete(s.apps, name)
+
+	if r.URL.Query().Get("delete_config_folder") == "true" {
+		s.removeAll(path.Join("/DATA/AppData", name))
+	}
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) getAppStatus(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[params["name"]]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found", params["name"]))
+		return
+	}
+
+	writeData(w, r, a.status)
+}
+
+func (s *Server) setAppStatus(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var action string
+
+	if !readJSON(w, r, &action) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[params["name"]]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found", params["name"]))
+		return
+	}
+
+	switch action {
+	case "start", "restart":
+		a.status = "running"
+	case "stop":
+		a.status = "stopped"
+	default:
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("unknown action %q", action))
+		return
+	}
+
+	a.logs = append(a.logs, fmt.Sprintf("%s: %s", a.name, action))
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) getAppContainers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[params["name"]]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found", params["name"]))
+		return
+	}
+
+	state := "exited"
+	if a.status == "running" {
+		state = "running"
+	}
+
+	containers := map[string]interface{}{}
+
+	for service, image := range serviceImages(a.project) {
+		containers[service] = map[string]interface{}{
+			"Id":    fmt.Sprintf("%s-%s-1", a.name, service),
+			"Image": image,
+			"State": state,
+		}
+	}
+
+	info, _ := a.project["x-casaos"].(map[string]interface{})
+	main, _ := info["main"].(string)
+
+	writeData(w, r, map[string]interface{}{
+		"main":       main,
+		"containers": containers,
+	})
+}
+
+func (s *Server) getAppLogs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[params["name"]]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found", params["name"]))
+		return
+	}
+
+	logs := a.logs
+
+	if lines, err := strconv.Atoi(r.URL.Query().Get("lines")); err == nil && lines < len(logs) {
+		logs = logs[len(logs)-lines:]
+	}
+
+	writeData(w, r, strings.Join(logs, "\n"))
+}
+
+func (s *Server) getCatalogAppCompose(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, store := range s.stores {
+		if compose, ok := store.catalog[params["id"]]; ok {
+			writeYAML(w, compose)
+			return
+		}
+	}
+
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("app %q not found in any app store", params["id"]))
+}
+
+func (s *Server) listAppStores(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stores := make([]interface{}, 0, len(s.stores))
+
+	for i, store := range s.stores {
+		stores = append(stores, map[string]interface{}{
+			"id":         i,
+			"url":        store.url,
+			"name":       store.name,
+			"store_root": path.Join("/var/lib/casaos/appstore", store.name),
+			"app_count":  len(store.catalog),
+		})
+	}
+
+	writeData(w, r, stores)
+}
+
+func (s *Server) registerAppStore(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	storeURL := r.URL.Query().Get("url")
+	if storeURL == "" {
+		writeError(w, r, http.StatusBadRequest, "missing url")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, store := range s.stores {
+		if store.url == storeURL {
+			writeError(w, r, http.StatusConflict, fmt.Sprintf("app store %q is already registered", storeURL))
+			return
+		}
+	}
+
+	s.stores = append(s.stores, &appStore{
+		url:     storeURL,
+		name:    strings.TrimSuffix(path.Base(storeURL), ".zip"),
+		catalog: map[string]string{},
+	})
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) unregisterAppStore(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := strconv.Atoi(params["id"])
+	if err != nil || i < 0 || i >= len(s.stores) {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("app store %s not found", params["id"]))
+		return
+	}
+
+	s.stores = append(s.stores[:i], s.stores[i+1:]...)
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) listAppStoreCatalog(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, store := range s.stores {
+		if strconv.Itoa(i) != params["id"] {
+			continue
+		}
+
+		apps := map[string]interface{}{}
+
+		for id, compose := range store.catalog {
+			var project map[string]interface{}
+
+			if err := yaml.Unmarshal([]byte(compose), &project); err != nil {
+				writeError(w, r, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			apps[id] = map[string]interface{}{
+				"store_info": storeInfo(project),
+				"compose":    projectJSON(project),
+			}
+		}
+
+		writeData(w, r, apps)
+
+		return
+	}
+
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("app store %s not found", params["id"]))
+}
+
+// readCompose reads a compose file from the request body, answering HTTP 400
+// and returning false when it is not a valid CasaOS app.
+func readCompose(w http.ResponseWriter, r *http.Request) (string, map[string]interface{}, bool) {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return "", nil, false
+	}
+
+	var project map[string]interface{}
+
+	if err := yaml.Unmarshal(b, &project); err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid compose file: %s", err))
+		return "", nil, false
+	}
+
+	if name, _ := project["name"].(string); name == "" {
+		writeError(w, r, http.StatusBadRequest, "compose file has no name")
+		return "", nil, false
+	}
+
+	if _, ok := project["x-casaos"].(map[string]interface{}); !ok {
+		writeError(w, r, http.StatusBadRequest, "compose file has no x-casaos extension")
+		return "", nil, false
+	}
+
+	return string(b), project, true
+}
+
+// storeInfo returns the x-casaos extension of a project as the app management
+// service reports it.
+func storeInfo(project map[string]interface{}) map[string]interface{} {
+	info := map[string]interface{}{}
+
+	if ext, ok := project["x-casaos"].(map[string]interface{}); ok {
+		for k, v := range ext {
+			info[k] = v
+		}
+	}
+
+	// Ports are numbers in many compose files but strings in the API.
+	if portMap, ok := info["port_map"]; ok {
+		info["port_map"] = fmt.Sprint(portMap)
+	}
+
+	return info
+}
+
+// projectJSON returns the services of a project with their ports in the long
+// syntax, as the app management service reports them.
+func projectJSON(project map[string]interface{}) map[string]interface{} {
+	services := map[string]interface{}{}
+
+	raw, _ := project["services"].(map[string]interface{})
+
+	for name, v := range raw {
+		service, _ := v.(map[string]interface{})
+		ports := []interface{}{}
+
+		entries, _ := service["ports"].([]interface{})
+
+		for _, entry := range entries {
+			if port, ok := longPort(entry); ok {
+				ports = append(ports, port)
+			}
+		}
+
+		services[name] = map[string]interface{}{
+			"image": service["image"],
+			"ports": ports,
+		}
+	}
+
+	return map[string]interface{}{
+		"name":     project["name"],
+		"services": services,
+	}
+}
+
+// longPort converts a compose port in either syntax to the long syntax.
+func longPort(entry interface{}) (map[string]interface{}, bool) {
+	switch port := entry.(type) {
+	case map[string]interface{}:
+		target, err := strconv.ParseInt(fmt.Sprint(port["target"]), 10, 64)
+		if err != nil {
+			return nil, false
+		}
+
+		protocol, _ := port["protocol"].(string)
+		if protocol == "" {
+			protocol = "tcp"
+		}
+
+		published := ""
+		if port["published"] != nil {
+			published = fmt.Sprint(port["published"])
+		}
+
+		return map[string]interface{}{
+			"target":    target,
+			"published": published,
+			"protocol":  protocol,
+		}, true
+	case string:
+		spec, protocol, found := strings.Cut(port, "/")
+		if !found {
+			protocol = "tcp"
+		}
+
+		parts := strings.Split(spec, ":")
+
+		target, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+		if err != nil {
+			return nil, false
+		}
+
+		published := ""
+		if len(parts) > 1 {
+			published = parts[len(parts)-2]
+		}
+
+		return map[string]interface{}{
+			"target":    target,
+			"published": published,
+			"protocol":  protocol,
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// serviceImages returns the image of every service of a project.
+func serviceImages(project map[string]interface{}) map[string]string {
+	images := map[string]string{}
+
+	services, _ := project["services"].(map[string]interface{})
+
+	for name, v := range services {
+		service, _ := v.(map[string]interface{})
+		image, _ := service["image"].(string)
+		images[name] = image
+	}
+
+	return images
+}
+
+// pullLogs returns the log lines of pulling and starting a project.
+func pullLogs(project map[string]interface{}) []string {
+	images := serviceImages(project)
+	services := make([]string, 0, len(images))
+
+	for service := range images {
+		services = append(services, service)
+	}
+
+	sort.Strings(services)
+
+	var logs []string
+
+	for _, service := range services {
+		logs = append(logs,
+			fmt.Sprintf("%s Pulling", images[service]),
+			fmt.Sprintf("%s Pulled", images[service]),
+			fmt.Sprintf("Container %s-%s-1 Started", project["name"], service),
+		)
+	}
+
+	return logs
+}
+
+const jellyfinCompose = `name: jellyfin
+services:
+  jellyfin:
+    image: linuxserver/jellyfin:10.8.13
+    environment:
+      PGID: "1000"
+      PUID: "1000"
+      TZ: UTC
+    ports:
+      - target: 8096
+        published: "8096"
+        protocol: tcp
+    volumes:
+      - type: bind
+        source: /DATA/AppData/jellyfin/config
+        target: /config
+      - type: bind
+        source: /DATA/Media
+        target: /media
+x-casaos:
+  architectures:
+    - amd64
+    - arm64
+  main: jellyfin
+  author: IceWhaleTech
+  category: Media
+  developer: Jellyfin
+  icon: https://cdn.jsdelivr.net/gh/IceWhaleTech/CasaOS-AppStore@main/Apps/Jellyfin/icon.png
+  tagline:
+    en_us: The Free Software Media System
+  title:
+    en_us: Jellyfin
+  description:
+    en_us: Jellyfin puts you in control of managing and streaming your media.
+  port_map: "8096"
+  scheme: http
+  store_app_id: Jellyfin
+`
+
+const syncthingCompose = `name: syncthing
+services:
+  syncthing:
+    image: linuxserver/syncthing:1.27.2
+    environment:
+      PGID: "1000"
+      PUID: "1000"
+      TZ: UTC
+    ports:
+      - target: 8384
+        published: "8384"
+        protocol: tcp
+      - target: 22000
+        published: "22000"
+        protocol: tcp
+      - target: 21027
+        published: "21027"
+        protocol: udp
+    volumes:
+      - type: bind
+        source: /DATA/AppData/syncthing/config
+        target: /config
+x-casaos:
+  architectures:
+    - amd64
+    - arm64
+  main: syncthing
+  author: IceWhaleTech
+  category: Backup
+  developer: Syncthing
+  icon: https://cdn.jsdelivr.net/gh/IceWhaleTech/CasaOS-AppStore@main/Apps/Syncthing/icon.png
+  tagline:
+    en_us: Continuous file synchronization
+  title:
+    en_us: Syncthing
+  description:
+    en_us: Syncthing synchronizes files between two or more computers in real time.
+  port_map: "8384"
+  scheme: http
+  store_app_id: Syncthing
+`
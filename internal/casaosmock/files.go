@@ -0,0 +1,503 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// file is a file or directory of the in-memory file system.
+type file struct {
+	isDir   bool
+	content []byte
+	mode    uint32
+	modTime time.Time
+}
+
+// upload is a file being uploaded in chunks, keyed by chunk number.
+type upload struct {
+	chunks      map[int][]byte
+	totalChunks int
+}
+
+// fileInfo is a file system entry as the file service reports it.
+type fileInfo struct {
+	Name  string    `json:"name"`
+	Path  string    `json:"path"`
+	Size  int64     `json:"size"`
+	IsDir bool      `json:"is_dir"`
+	Mode  uint32    `json:"mode"`
+	Date  time.Time `json:"date"`
+}
+
+func (s *Server) seedFiles() {
+	s.files = map[string]*file{}
+	s.uploads = map[string]*upload{}
+
+	for _, dir := range []string{"/DATA/AppData", "/DATA/Documents", "/DATA/Downloads", "/DATA/Gallery", "/DATA/Media"} {
+		s.mkdirAll(dir)
+	}
+
+	s.files["/DATA/Documents/README.md"] = &file{
+		content: []byte("# Welcome to CasaOS\n"),
+		mode:    0o644,
+		modTime: epoch,
+	}
+}
+
+func (s *Server) registerFileRoutes() {
+	s.handle(http.MethodGet, "/v1/folder", s.listFolder)
+	s.handle(http.MethodPost, "/v1/folder", s.createFolder)
+	s.handle(http.MethodPut, "/v1/folder/name", s.renamePath)
+	s.handle(http.MethodDelete, "/v1/batch", s.deletePaths)
+	s.handle(http.MethodGet, "/v1/file", s.downloadFile)
+	s.handle(http.MethodPost, "/v1/file", s.createFile)
+	s.handle(http.MethodPut, "/v1/file", s.writeFile)
+	s.handle(http.MethodGet, "/v1/file/content", s.getFileContent)
+	s.handle(http.MethodGet, "/v1/file/upload", s.checkChunk)
+	s.handle(http.MethodPost, "/v1/file/upload", s.uploadChunk)
+}
+
+// WriteFile creates or replaces a file behind the provider's back. Missing
+// parent directories are created.
+func (s *Server) WriteFile(name string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mkdirAll(path.Dir(name))
+
+	s.files[path.Clean(name)] = &file{
+		content: append([]byte(nil), content...),
+		mode:    0o644,
+		modTime: s.tick(),
+	}
+}
+
+// ReadFile returns the content of a file, and false when there is no such
+// file.
+func (s *Server) ReadFile(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[path.Clean(name)]
+	if !ok || f.isDir {
+		return nil, false
+	}
+
+	return append([]byte(nil), f.content...), true
+}
+
+// Exists reports whether a file or directory exists.
+func (s *Server) Exists(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.files[path.Clean(name)]
+
+	return ok
+}
+
+// Remove deletes a file or directory tree behind the provider's back.
+func (s *Server) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeAll(path.Clean(name))
+}
+
+func (s *Server) listFolder(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	dir := path.Clean(r.URL.Query().Get("path"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[dir]
+
+	switch {
+	case !ok:
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("%s does not exist", dir))
+		return
+	case !f.isDir:
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("%s is not a directory", dir))
+		return
+	}
+
+	content := []fileInfo{}
+
+	for name, child := range s.files {
+		if name != dir && path.Dir(name) == dir {
+			content = append(content, newFileInfo(name, child))
+		}
+	}
+
+	sort.Slice(content, func(i, j int) bool {
+		return content[i].Name < content[j].Name
+	})
+
+	writeData(w, r, map[string]interface{}{
+		"content": content,
+		"total":   len(content),
+		"index":   1,
+		"size":    len(content),
+	})
+}
+
+func (s *Server) createFolder(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		Path string `json:"path"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	dir := path.Clean(in.Path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkCreate(w, r, dir) {
+		return
+	}
+
+	s.files[dir] = &file{
+		isDir:   true,
+		mode:    0o755,
+		modTime: s.tick(),
+	}
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) renamePath(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		OldPath string `json:"old_path"`
+		NewPath string `json:"new_path"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	oldPath, newPath := path.Clean(in.OldPath), path.Clean(in.NewPath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.files[oldPath]; !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("%s does not exist", oldPath))
+		return
+	}
+
+	if !s.checkCreate(w, r, newPath) {
+		return
+	}
+
+	moved := map[string]*file{}
+
+	for name, f := range s.files {
+		if name == oldPath || strings.HasPrefix(name, oldPath+"/") {
+			moved[newPath+strings.TrimPrefix(name, oldPath)] = f
+			delete(s.files, name)
+		}
+	}
+
+	for name, f := range moved {
+		s.files[name] = f
+	}
+
+	s.files[newPath].modTime = s.tick()
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) deletePaths(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var paths []string
+
+	if !readJSON(w, r, &paths) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range paths {
+		if _, ok := s.files[path.Clean(p)]; !ok {
+			writeError(w, r, http.StatusNotFound, fmt.Sprintf("%s does not exist", p))
+			return
+		}
+	}
+
+	for _, p := range paths {
+		s.removeAll(path.Clean(p))
+	}
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) downloadFile(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	name := path.Clean(r.URL.Query().Get("path"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[name]
+	if !ok || f.isDir {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("%s is not a file", name))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(f.content)
+}
+
+func (s *Server) createFile(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		Path string `json:"path"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	name := path.Clean(in.Path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkCreate(w, r, name) {
+		return
+	}
+
+	s.files[name] = &file{
+		mode:    0o644,
+		modTime: s.tick(),
+	}
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) writeFile(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		FilePath    string `json:"file_path"`
+		FileContent string `json:"file_content"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	name := path.Clean(in.FilePath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[name]
+
+	switch {
+	case !ok:
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("%s does not exist", name))
+		return
+	case f.isDir:
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("%s is a directory", name))
+		return
+	}
+
+	f.content = []byte(in.FileContent)
+	f.modTime = s.tick()
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) getFileContent(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	name := path.Clean(r.URL.Query().Get("path"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[name]
+	if !ok || f.isDir {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("%s is not a file", name))
+		return
+	}
+
+	writeData(w, r, string(f.content))
+}
+
+// checkChunk answers whether a chunk of a file upload has arrived already, as
+// resumable.js asks before sending a chunk: HTTP 200 when it has, HTTP 204
+// when it still needs to be sent.
+func (s *Server) checkChunk(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	query := r.URL.Query()
+
+	chunkNumber, err := strconv.Atoi(query.Get("chunkNumber"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid chunkNumber")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.uploads[query.Get("identifier")]; ok {
+		if chunk, ok := u.chunks[chunkNumber]; ok && strconv.Itoa(len(chunk)) == query.Get("currentChunkSize") {
+			writeData(w, r, nil)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// uploadChunk stores a chunk of a file upload as resumable.js sends it, and
+// writes the file once every chunk has arrived. Existing files are replaced.
+func (s *Server) uploadChunk(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid upload: %s", err))
+		return
+	}
+
+	chunkNumber, err1 := strconv.Atoi(r.FormValue("chunkNumber"))
+	totalChunks, err2 := strconv.Atoi(r.FormValue("totalChunks"))
+
+	if err1 != nil || err2 != nil || chunkNumber < 1 || chunkNumber > totalChunks {
+		writeError(w, r, http.StatusBadRequest, "invalid chunkNumber or totalChunks")
+		return
+	}
+
+	identifier := r.FormValue("identifier")
+	name := path.Join(r.FormValue("path"), r.FormValue("relativePath"))
+
+	part, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid upload: %s", err))
+		return
+	}
+	defer part.Close()
+
+	chunk, err := io.ReadAll(part)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid upload: %s", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if parent, ok := s.files[path.Dir(name)]; !ok || !parent.isDir {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("directory %s does not exist", path.Dir(name)))
+		return
+	}
+
+	if f, ok := s.files[name]; ok && f.isDir {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("%s is a directory", name))
+		return
+	}
+
+	u, ok := s.uploads[identifier]
+	if !ok {
+		u = &upload{
+			chunks:      map[int][]byte{},
+			totalChunks: totalChunks,
+		}
+		s.uploads[identifier] = u
+	}
+
+	u.chunks[chunkNumber] = chunk
+
+	if len(u.chunks) < u.totalChunks {
+		writeData(w, r, nil)
+		return
+	}
+
+	var content []byte
+
+	for i := 1; i <= u.totalChunks; i++ {
+		content = append(content, u.chunks[i]...)
+	}
+
+	delete(s.uploads, identifier)
+
+	s.files[name] = &file{
+		content: content,
+		mode:    0o644,
+		modTime: s.tick(),
+	}
+
+	writeData(w, r, nil)
+}
+
+// checkCreate answers with an error and returns false when name cannot be
+// created: it exists already or its parent directory does not. It must be
+// called with mu held.
+func (s *Server) checkCreate(w http.ResponseWriter, r *http.Request, name string) bool {
+	if _, ok := s.files[name]; ok {
+		writeError(w, r, http.StatusConflict, fmt.Sprintf("%s already exists", name))
+		return false
+	}
+
+	if parent, ok := s.files[path.Dir(name)]; !ok || !parent.isDir {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("directory %s does not exist", path.Dir(name)))
+		return false
+	}
+
+	return true
+}
+
+// mkdirAll creates dir and its missing parents. It must be called with mu
+// held.
+func (s *Server) mkdirAll(dir string) {
+	for d := path.Clean(dir); ; d = path.Dir(d) {
+		if _, ok := s.files[d]; !ok {
+			s.files[d] = &file{
+				isDir:   true,
+				mode:    0o755,
+				modTime: epoch,
+			}
+		}
+
+		if d == "/" {
+			return
+		}
+	}
+}
+
+// removeAll deletes name and everything below it. It must be called with mu
+// held.
+func (s *Server) removeAll(name string) {
+	for p := range s.files {
+		if p == name || strings.HasPrefix(p, name+"/") {
+			delete(s.files, p)
+		}
+	}
+}
+
+// tick advances the clock of the file system by a minute and returns the new
+// time, so modification times are deterministic. It must be called with mu
+// held.
+func (s *Server) tick() time.Time {
+	s.clock++
+
+	return epoch.Add(time.Duration(s.clock) * time.Minute)
+}
+
+func newFileInfo(name string, f *file) fileInfo {
+	return fileInfo{
+		Name:  path.Base(name),
+		Path:  name,
+		Size:  int64(len(f.content)),
+		IsDir: f.isDir,
+		Mode:  f.mode,
+		Date:  f.modTime,
+	}
+}
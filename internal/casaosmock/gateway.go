@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// gatewayRestartDelay is how long the gateway takes to answer on a new port.
+const gatewayRestartDelay = 100 * time.Millisecond
+
+// gatewayListener serves the device on a port the gateway was moved to.
+type gatewayListener struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+func (s *Server) seedGateway() {
+	u, _ := url.Parse(s.URL)
+
+	s.gatewayPort = u.Port()
+	s.gatewayListeners = map[string]*gatewayListener{}
+}
+
+func (s *Server) registerGatewayRoutes() {
+	s.handlePublic(http.MethodGet, "/", s.getWebUI)
+	s.handle(http.MethodGet, "/v1/gateway/port", s.getGatewayPort)
+	s.handle(http.MethodPut, "/v1/gateway/port", s.putGatewayPort)
+}
+
+// GatewayPort returns the port the gateway listens on.
+func (s *Server) GatewayPort() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.gatewayPort
+}
+
+// SetGatewayPort moves the gateway to port behind the provider's back, as
+// the settings of the dashboard do.
+func (s *Server) SetGatewayPort(port string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.moveGateway(port)
+}
+
+// PortRequests returns how many requests were received on port.
+func (s *Server) PortRequests(port string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.portRequests[port]
+}
+
+// Close stops the server on every port it listens on.
+func (s *Server) Close() {
+	s.mu.Lock()
+
+	for _, gl := range s.gatewayListeners {
+		_ = gl.server.Close()
+		_ = gl.listener.Close()
+	}
+
+	s.gatewayListeners = nil
+
+	s.mu.Unlock()
+
+	s.Server.Close()
+}
+
+// moveGateway makes the device answer on port after gatewayRestartDelay. It
+// keeps answering on its original address as well, so a test can go on with
+// the provider configuration it started with. It must be called with mu held.
+func (s *Server) moveGateway(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+
+	u, _ := url.Parse(s.URL)
+
+	if _, ok := s.gatewayListeners[port]; !ok && port != u.Port() {
+		l, err := net.Listen("tcp", net.JoinHostPort(u.Hostname(), port))
+		if err != nil {
+			return fmt.Errorf("port %s is already in use", port)
+		}
+
+		gl := &gatewayListener{
+			server:   &http.Server{Handler: http.HandlerFunc(s.serveHTTP), ReadHeaderTimeout: time.Minute},
+			listener: l,
+		}
+
+		s.gatewayListeners[port] = gl
+
+		// Connections wait in the backlog until the gateway is back.
+		time.AfterFunc(gatewayRestartDelay, func() {
+			_ = gl.server.Serve(l)
+		})
+	}
+
+	s.gatewayPort = port
+
+	return nil
+}
+
+// getWebUI serves the start page of the dashboard.
+func (s *Server) getWebUI(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = io.WriteString(w, "<!DOCTYPE html><title>CasaOS</title>")
+}
+
+func (s *Server) getGatewayPort(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, r, s.gatewayPort)
+}
+
+func (s *Server) putGatewayPort(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		Port string `json:"port"`
+	}
+
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.moveGateway(req.Port); err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeData(w, r, nil)
+}
@@ -0,0 +1,232 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// sambaShare is a folder shared over SMB.
+type sambaShare struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Anonymous bool   `json:"anonymous"`
+}
+
+// sambaConnection is a remote SMB server mounted on the device.
+type sambaConnection struct {
+	ID         int64  `json:"id"`
+	Host       string `json:"host"`
+	Port       string `json:"port"`
+	Username   string `json:"username"`
+	Password   string `json:"password,omitempty"`
+	MountPoint string `json:"mount_point"`
+}
+
+func (s *Server) registerSambaRoutes() {
+	s.handle(http.MethodGet, "/v1/samba/shares", s.listSambaShares)
+	s.handle(http.MethodPost, "/v1/samba/shares", s.createSambaShares)
+	s.handle(http.MethodDelete, "/v1/samba/shares/{id}", s.deleteSambaShare)
+	s.handle(http.MethodGet, "/v1/samba/connections", s.listSambaConnections)
+	s.handle(http.MethodPost, "/v1/samba/connections", s.createSambaConnection)
+	s.handle(http.MethodDelete, "/v1/samba/connections/{id}", s.deleteSambaConnection)
+}
+
+// RemoveSambaShare deletes an SMB share behind the provider's back.
+func (s *Server) RemoveSambaShare(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, share := range s.sambaShares {
+		if share.Name == name {
+			s.sambaShares = append(s.sambaShares[:i], s.sambaShares[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Server) listSambaShares(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shares := []sambaShare{}
+
+	for _, share := range s.sambaShares {
+		shares = append(shares, *share)
+	}
+
+	writeData(w, r, shares)
+}
+
+func (s *Server) createSambaShares(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in []sambaShare
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, share := range in {
+		dir := path.Clean(share.Path)
+
+		if f, ok := s.files[dir]; !ok || !f.isDir {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("path %s does not exist", share.Path))
+			return
+		}
+
+		if share.Name == "" {
+			share.Name = path.Base(dir)
+		}
+
+		for _, existing := range s.sambaShares {
+			if existing.Name == share.Name {
+				writeError(w, r, http.StatusConflict, fmt.Sprintf("share %q already exists", share.Name))
+				return
+			}
+		}
+	}
+
+	for _, share := range in {
+		s.nextSambaID++
+
+		if share.Name == "" {
+			share.Name = path.Base(path.Clean(share.Path))
+		}
+
+		s.sambaShares = append(s.sambaShares, &sambaShare{
+			ID:        s.nextSambaID,
+			Name:      share.Name,
+			Path:      path.Clean(share.Path),
+			Anonymous: share.Anonymous,
+		})
+	}
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) deleteSambaShare(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, share := range s.sambaShares {
+		if strconv.FormatInt(share.ID, 10) == params["id"] {
+			s.sambaShares = append(s.sambaShares[:i], s.sambaShares[i+1:]...)
+			writeData(w, r, nil)
+
+			return
+		}
+	}
+
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("share %s not found", params["id"]))
+}
+
+// RemoveSambaConnection unmounts a remote SMB server behind the provider's
+// back.
+func (s *Server) RemoveSambaConnection(host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, connection := range s.sambaConnections {
+		if connection.Host == host {
+			s.removeAll(connection.MountPoint)
+			s.sambaConnections = append(s.sambaConnections[:i], s.sambaConnections[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (s *Server) listSambaConnections(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	connections := []sambaConnection{}
+
+	for _, connection := range s.sambaConnections {
+		c := *connection
+		c.Password = ""
+		connections = append(connections, c)
+	}
+
+	writeData(w, r, connections)
+}
+
+// createSambaConnection mounts a remote SMB server. Hosts under the reserved
+// .invalid domain are unreachable, and the password "wrong" is rejected.
+func (s *Server) createSambaConnection(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in sambaConnection
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	switch {
+	case in.Host == "" || in.Username == "":
+		writeError(w, r, http.StatusBadRequest, "host and username are required")
+		return
+	case strings.HasSuffix(in.Host, ".invalid"):
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("connect to host failed: dial tcp %s:%s: no such host", in.Host, in.Port))
+		return
+	case in.Password == "wrong":
+		writeError(w, r, http.StatusBadRequest, "connect to host failed: NT_STATUS_LOGON_FAILURE")
+		return
+	}
+
+	if in.Port == "" {
+		in.Port = "445"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.sambaConnections {
+		if existing.Host == in.Host && existing.Port == in.Port {
+			writeError(w, r, http.StatusConflict, fmt.Sprintf("%s:%s is already connected", in.Host, in.Port))
+			return
+		}
+	}
+
+	s.nextSambaConnection++
+
+	connection := &sambaConnection{
+		ID:         s.nextSambaConnection,
+		Host:       in.Host,
+		Port:       in.Port,
+		Username:   in.Username,
+		Password:   in.Password,
+		MountPoint: path.Join("/mnt", in.Host),
+	}
+
+	s.mkdirAll(connection.MountPoint)
+	s.sambaConnections = append(s.sambaConnections, connection)
+
+	out := *connection
+	out.Password = ""
+
+	writeData(w, r, out)
+}
+
+func (s *Server) deleteSambaConnection(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, connection := range s.sambaConnections {
+		if strconv.FormatInt(connection.ID, 10) == params["id"] {
+			s.removeAll(connection.MountPoint)
+			s.sambaConnections = append(s.sambaConnections[:i], s.sambaConnections[i+1:]...)
+			writeData(w, r, nil)
+
+			return
+		}
+	}
+
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("connection %s not found", params["id"]))
+}
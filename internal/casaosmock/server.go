@@ -0,0 +1,378 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package casaosmock is an in-memory stand-in for the CasaOS HTTP API. It
+// serves the user, app management, file, Samba, storage, system and gateway
+// endpoints the provider talks to, so acceptance tests can run without a
+// device.
+//
+// Every server starts from the same state, so IDs and timestamps are
+// deterministic. Faults such as latency, server errors and expired tokens can
+// be injected to exercise the error handling of the provider.
+package casaosmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials of the owner account every server starts with.
+const (
+	Username = "casaos"
+	Password = "casaos"
+)
+
+// epoch is the modification time of every seeded file, so listings do not
+// depend on when the test runs.
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Server is a mock CasaOS device listening on a local port.
+type Server struct {
+	*httptest.Server
+
+	routes []route
+
+	// mu guards everything below.
+	mu           sync.Mutex
+	faults       []*Fault
+	requests     map[string]int
+	portRequests map[string]int
+
+	tokenLifetime time.Duration
+	accessTokens  map[string]time.Time
+	refreshTokens map[string]bool
+	nextToken     int
+
+	users           []*user
+	registrationKey string
+	customData      map[string]json.RawMessage
+
+	apps   map[string]*app
+	stores []*appStore
+
+	files   map[string]*file
+	uploads map[string]*upload
+	clock   int
+
+	sambaShares []*sambaShare
+	nextSambaID int64
+
+	sambaConnections    []*sambaConnection
+	nextSambaConnection int64
+
+	disks       []*disk
+	storages    []*storage
+	merges      []*merge
+	nextMergeID int64
+
+	system systemInfo
+
+	gatewayPort      string
+	gatewayListeners map[string]*gatewayListener
+}
+
+// Fault makes matching requests slow or fail.
+type Fault struct {
+	// Method matches the request method, any method when empty.
+	Method string
+
+	// Path matches requests whose path starts with it, any path when empty.
+	Path string
+
+	// Latency delays the response.
+	Latency time.Duration
+
+	// Status, when set, is returned instead of handling the request.
+	Status int
+
+	// Times limits the fault to the next Times matching requests, every
+	// matching request when zero.
+	Times int
+
+	// After lets the next After matching requests through untouched before
+	// the fault applies.
+	After int
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  func(w http.ResponseWriter, r *http.Request, params map[string]string)
+	public   bool
+}
+
+// NewServer starts a mock CasaOS device. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		requests:      map[string]int{},
+		portRequests:  map[string]int{},
+		tokenLifetime: time.Hour,
+		accessTokens:  map[string]time.Time{},
+		refreshTokens: map[string]bool{},
+	}
+
+	s.seed()
+
+	s.registerUserRoutes()
+	s.registerAppManagementRoutes()
+	s.registerFileRoutes()
+	s.registerSambaRoutes()
+	s.registerStorageRoutes()
+	s.registerSystemRoutes()
+	s.registerWebRoutes()
+	s.registerGatewayRoutes()
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	// The gateway starts out on the port the server listens on.
+	s.seedGateway()
+
+	return s
+}
+
+// seed sets up the state every server starts with.
+func (s *Server) seed() {
+	s.seedUsers()
+	s.apps = map[string]*app{}
+	s.seedAppStores()
+	s.seedFiles()
+	s.seedStorage()
+	s.seedSystem()
+}
+
+// AddFault injects f into the handling of subsequent requests.
+func (s *Server) AddFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// ExpireTokens makes every access token handed out so far expire, as if the
+// device had been idle for too long. Refresh tokens stay valid.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for token := range s.accessTokens {
+		s.accessTokens[token] = time.Time{}
+	}
+}
+
+// SetTokenLifetime sets how long access tokens handed out from now on are
+// valid. It defaults to an hour.
+func (s *Server) SetTokenLifetime(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokenLifetime = d
+}
+
+// Requests returns how many requests were received for method and path.
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[method+" "+path]
+}
+
+func (s *Server) handle(method, pattern string, handler func(w http.ResponseWriter, r *http.Request, params map[string]string)) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+func (s *Server) handlePublic(method, pattern string, handler func(w http.ResponseWriter, r *http.Request, params map[string]string)) {
+	s.handle(method, pattern, handler)
+	s.routes[len(s.routes)-1].public = true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	status, latency := s.applyFaults(r)
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status != 0 {
+		writeError(w, r, status, "injected fault")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	for _, rt := range s.routes {
+		params, ok := rt.match(r.Method, segments)
+		if !ok {
+			continue
+		}
+
+		if !rt.public && !s.authorized(r) {
+			writeError(w, r, http.StatusUnauthorized, "token expired or invalid")
+			return
+		}
+
+		rt.handler(w, r, params)
+
+		return
+	}
+
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+}
+
+// applyFaults counts the request and returns the status and latency of the
+// faults it matches.
+func (s *Server) applyFaults(r *http.Request) (int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[r.Method+" "+r.URL.Path]++
+
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		if _, port, err := net.SplitHostPort(addr.String()); err == nil {
+			s.portRequests[port]++
+		}
+	}
+
+	var (
+		status  int
+		latency time.Duration
+		active  []*Fault
+	)
+
+	for _, f := range s.faults {
+		if (f.Method == "" || f.Method == r.Method) && strings.HasPrefix(r.URL.Path, f.Path) {
+			if f.After > 0 {
+				f.After--
+				active = append(active, f)
+
+				continue
+			}
+
+			latency += f.Latency
+
+			if status == 0 {
+				status = f.Status
+			}
+
+			if f.Times > 0 {
+				f.Times--
+
+				if f.Times == 0 {
+					continue
+				}
+			}
+		}
+
+		active = append(active, f)
+	}
+
+	s.faults = active
+
+	return status, latency
+}
+
+func (rt route) match(method string, segments []string) (map[string]string, bool) {
+	if rt.method != method || len(rt.segments) != len(segments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[strings.Trim(segment, "{}")] = segments[i]
+			continue
+		}
+
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// envelope is the JSON wrapper CasaOS puts around every response.
+type envelope struct {
+	Success int         `json:"success,omitempty"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// isV1 reports whether r is for a v1 service, which reports its status in
+// the envelope as well.
+func isV1(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/v1/")
+}
+
+func writeData(w http.ResponseWriter, r *http.Request, data interface{}) {
+	env := envelope{
+		Message: "ok",
+		Data:    data,
+	}
+
+	if isV1(r) {
+		env.Success = http.StatusOK
+	}
+
+	writeJSON(w, http.StatusOK, env)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	env := envelope{
+		Message: message,
+	}
+
+	if isV1(r) {
+		env.Success = status
+	}
+
+	writeJSON(w, status, env)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeYAML(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = io.WriteString(w, body)
+}
+
+// readJSON decodes the request body into v, answering HTTP 400 and returning
+// false when it is not valid JSON.
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return false
+	}
+
+	return true
+}
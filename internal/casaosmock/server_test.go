@@ -0,0 +1,332 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// call sends a JSON request to s and decodes the response envelope.
+func call(t *testing.T, s *Server, method, path, token string, in interface{}) (int, envelope) {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		t.Fatalf("decoding response of %s %s: %s", method, path, err)
+	}
+
+	return resp.StatusCode, env
+}
+
+func login(t *testing.T, s *Server) string {
+	t.Helper()
+
+	status, env := call(t, s, http.MethodPost, "/v1/users/login", "", map[string]string{
+		"username": Username,
+		"password": Password,
+	})
+	if status != http.StatusOK {
+		t.Fatalf("login failed with HTTP %d: %s", status, env.Message)
+	}
+
+	data := env.Data.(map[string]interface{})
+	token := data["token"].(map[string]interface{})
+
+	return token["access_token"].(string)
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if status, _ := call(t, s, http.MethodGet, "/v2/app_management/compose", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("expected HTTP 401 without a token, got %d", status)
+	}
+
+	token := login(t, s)
+
+	if status, _ := call(t, s, http.MethodGet, "/v2/app_management/compose", token, nil); status != http.StatusOK {
+		t.Errorf("expected HTTP 200 with a token, got %d", status)
+	}
+
+	s.ExpireTokens()
+
+	status, env := call(t, s, http.MethodGet, "/v1/sys/version", token, nil)
+	if status != http.StatusUnauthorized || env.Success != http.StatusUnauthorized {
+		t.Errorf("expected an expired token to be rejected by a v1 service, got HTTP %d, success %d", status, env.Success)
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	token := login(t, s)
+
+	s.AddFault(Fault{
+		Method: http.MethodGet,
+		Path:   "/v1/sys",
+		Status: http.StatusServiceUnavailable,
+		Times:  2,
+	})
+
+	for i, want := range []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK} {
+		if status, _ := call(t, s, http.MethodGet, "/v1/sys/version", token, nil); status != want {
+			t.Errorf("request %d: expected HTTP %d, got %d", i, want, status)
+		}
+	}
+
+	if n := s.Requests(http.MethodGet, "/v1/sys/version"); n != 3 {
+		t.Errorf("expected 3 requests to be counted, got %d", n)
+	}
+
+	s.AddFault(Fault{
+		Method: http.MethodGet,
+		Path:   "/v1/sys",
+		Status: http.StatusServiceUnavailable,
+		Times:  1,
+		After:  1,
+	})
+
+	for i, want := range []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusOK} {
+		if status, _ := call(t, s, http.MethodGet, "/v1/sys/version", token, nil); status != want {
+			t.Errorf("request %d after a delayed fault: expected HTTP %d, got %d", i, want, status)
+		}
+	}
+
+	s.AddFault(Fault{Latency: 50 * time.Millisecond})
+
+	start := time.Now()
+	call(t, s, http.MethodGet, "/v1/sys/version", token, nil)
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the request to be delayed, took %s", elapsed)
+	}
+}
+
+func TestServer_AppLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	token := login(t, s)
+
+	req, err := http.NewRequest(http.MethodPost, s.URL+"/v2/app_management/compose", bytes.NewBufferString(`name: whoami
+services:
+  whoami:
+    image: traefik/whoami:v1.10
+    ports:
+      - "8080:80"
+x-casaos:
+  main: whoami
+  port_map: 8080
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("install failed with HTTP %d", resp.StatusCode)
+	}
+
+	if status := s.AppStatus("whoami"); status != "running" {
+		t.Errorf("expected the app to run after install, got %q", status)
+	}
+
+	if !s.Exists("/DATA/AppData/whoami") {
+		t.Error("expected the app data folder to be created")
+	}
+
+	_, env := call(t, s, http.MethodGet, "/v2/app_management/compose", token, nil)
+
+	app := env.Data.(map[string]interface{})["whoami"].(map[string]interface{})
+	info := app["store_info"].(map[string]interface{})
+	port := app["compose"].(map[string]interface{})["services"].(map[string]interface{})["whoami"].(map[string]interface{})["ports"].([]interface{})[0].(map[string]interface{})
+
+	if info["port_map"] != "8080" {
+		t.Errorf("expected port_map to be reported as a string, got %#v", info["port_map"])
+	}
+
+	if port["target"] != float64(80) || port["published"] != "8080" || port["protocol"] != "tcp" {
+		t.Errorf("expected the short port syntax in long form, got %#v", port)
+	}
+
+	if status, _ := call(t, s, http.MethodPut, "/v2/app_management/compose/whoami/status", token, "stop"); status != http.StatusOK {
+		t.Errorf("stop failed with HTTP %d", status)
+	}
+
+	if status := s.AppStatus("whoami"); status != "stopped" {
+		t.Errorf("expected the app to be stopped, got %q", status)
+	}
+
+	if status, _ := call(t, s, http.MethodDelete, "/v2/app_management/compose/whoami?delete_config_folder=true", token, nil); status != http.StatusOK {
+		t.Errorf("uninstall failed with HTTP %d", status)
+	}
+
+	if s.Exists("/DATA/AppData/whoami") {
+		t.Error("expected the app data folder to be deleted")
+	}
+}
+
+func TestServer_Files(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	token := login(t, s)
+
+	if status, _ := call(t, s, http.MethodPost, "/v1/folder", token, map[string]string{"path": "/DATA/Documents/tax"}); status != http.StatusOK {
+		t.Fatalf("creating folder failed with HTTP %d", status)
+	}
+
+	s.WriteFile("/DATA/Documents/tax/2023.txt", []byte("paid"))
+
+	if status, _ := call(t, s, http.MethodPut, "/v1/folder/name", token, map[string]string{
+		"old_path": "/DATA/Documents/tax",
+		"new_path": "/DATA/Documents/taxes",
+	}); status != http.StatusOK {
+		t.Fatalf("renaming folder failed with HTTP %d", status)
+	}
+
+	if content, ok := s.ReadFile("/DATA/Documents/taxes/2023.txt"); !ok || string(content) != "paid" {
+		t.Errorf("expected the file to move with its folder, got %q, %t", content, ok)
+	}
+
+	if status, _ := call(t, s, http.MethodPost, "/v1/folder", token, map[string]string{"path": "/DATA/missing/child"}); status != http.StatusNotFound {
+		t.Errorf("expected HTTP 404 for a missing parent, got %d", status)
+	}
+}
+
+func TestServer_Upload(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	token := login(t, s)
+
+	// Chunks may arrive in any order, the file appears with the last one.
+	for _, chunk := range []struct {
+		number  string
+		content string
+	}{
+		{"2", "world"},
+		{"1", "hello "},
+	} {
+		if s.Exists("/DATA/Documents/hello.txt") {
+			t.Fatal("expected the file to appear only once every chunk arrived")
+		}
+
+		var body bytes.Buffer
+
+		form := multipart.NewWriter(&body)
+
+		for name, value := range map[string]string{
+			"path":         "/DATA/Documents",
+			"relativePath": "hello.txt",
+			"identifier":   "11-hellotxt",
+			"chunkNumber":  chunk.number,
+			"totalChunks":  "2",
+		} {
+			_ = form.WriteField(name, value)
+		}
+
+		part, _ := form.CreateFormFile("file", "hello.txt")
+		_, _ = part.Write([]byte(chunk.content))
+		_ = form.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/v1/file/upload", &body)
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("uploading chunk %s failed with HTTP %d", chunk.number, resp.StatusCode)
+		}
+	}
+
+	if content, ok := s.ReadFile("/DATA/Documents/hello.txt"); !ok || string(content) != "hello world" {
+		t.Errorf("expected the chunks to be joined in order, got %q, %t", content, ok)
+	}
+}
+
+func TestServer_Gateway(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	token := login(t, s)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	l.Close()
+
+	if status, env := call(t, s, http.MethodPut, "/v1/gateway/port", token, map[string]string{"port": "0"}); status != http.StatusBadRequest {
+		t.Errorf("expected an invalid port to be rejected, got HTTP %d: %s", status, env.Message)
+	}
+
+	if status, env := call(t, s, http.MethodPut, "/v1/gateway/port", token, map[string]string{"port": port}); status != http.StatusOK {
+		t.Fatalf("moving the gateway failed with HTTP %d: %s", status, env.Message)
+	}
+
+	// The request waits until the gateway answers on the new port.
+	resp, err := http.Get("http://127.0.0.1:" + port + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the web UI to answer on the new port, got HTTP %d", resp.StatusCode)
+	}
+
+	if n := s.PortRequests(port); n != 1 {
+		t.Errorf("expected 1 request on the new port, got %d", n)
+	}
+
+	if status, env := call(t, s, http.MethodGet, "/v1/gateway/port", token, nil); status != http.StatusOK || env.Data != port {
+		t.Errorf("expected the original address to keep answering with port %s, got HTTP %d: %v", port, status, env.Data)
+	}
+}
@@ -0,0 +1,487 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// disk is a physical disk attached to the device.
+type disk struct {
+	Path        string      `json:"path"`
+	Model       string      `json:"model"`
+	Serial      string      `json:"serial"`
+	Size        uint64      `json:"size"`
+	Type        string      `json:"type"`
+	Health      bool        `json:"health"`
+	SmartStatus string      `json:"smart_status"`
+	Temperature int         `json:"temperature"`
+	Children    []partition `json:"children"`
+
+	// State is "formatting" while a storage is being created on the disk.
+	State string `json:"state,omitempty"`
+
+	// format is the storage being created while the disk is formatting.
+	format *storage
+
+	// formatPolls is how many more listings report the disk as formatting.
+	formatPolls int
+}
+
+// partition is a partition of a disk.
+type partition struct {
+	Path       string `json:"path"`
+	Size       uint64 `json:"size"`
+	FSType     string `json:"fstype"`
+	MountPoint string `json:"mountpoint"`
+}
+
+// storage is a partition mounted as a CasaOS storage.
+type storage struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	MountPoint string `json:"mount_point"`
+	Disk       string `json:"disk"`
+	Type       string `json:"type"`
+	Size       uint64 `json:"size"`
+	Used       uint64 `json:"used"`
+	Avail      uint64 `json:"avail"`
+}
+
+// merge is a MergerFS pool of storages mounted as one.
+type merge struct {
+	ID                int64    `json:"id"`
+	FSType            string   `json:"fstype"`
+	MountPoint        string   `json:"mount_point"`
+	SourceVolumePaths []string `json:"source_volume_paths"`
+	Size              uint64   `json:"size"`
+	Used              uint64   `json:"used"`
+	Avail             uint64   `json:"avail"`
+}
+
+// mergeRequest is the body of a request creating or changing a merge.
+type mergeRequest struct {
+	MountPoint        string   `json:"mount_point"`
+	SourceVolumePaths []string `json:"source_volume_paths"`
+}
+
+const gib = 1 << 30
+
+// formatPolls is how many disk or storage listings report a disk as
+// formatting before the format finishes, so clients have to poll.
+const formatPolls = 2
+
+// filesystems are the filesystems a disk can be formatted with.
+var filesystems = map[string]bool{"ext4": true, "xfs": true, "btrfs": true}
+
+func (s *Server) seedStorage() {
+	s.disks = []*disk{
+		{
+			Path:        "/dev/sda",
+			Model:       "Samsung SSD 870 EVO 250GB",
+			Serial:      "S6PENL0T123456A",
+			Size:        250 * gib,
+			Type:        "SSD",
+			Health:      true,
+			SmartStatus: "PASSED",
+			Temperature: 34,
+			Children: []partition{
+				{Path: "/dev/sda1", Size: 250 * gib, FSType: "ext4", MountPoint: "/"},
+			},
+		},
+		{
+			Path:        "/dev/sdb",
+			Model:       "WDC WD40EFAX-68JH4N1",
+			Serial:      "WD-WX12D3456789",
+			Size:        4000 * gib,
+			Type:        "HDD",
+			Health:      true,
+			SmartStatus: "PASSED",
+			Temperature: 38,
+			Children: []partition{
+				{Path: "/dev/sdb1", Size: 4000 * gib, FSType: "ext4", MountPoint: "/media/Storage1"},
+			},
+		},
+		{
+			Path:        "/dev/sdc",
+			Model:       "ST2000DM008-2FR102",
+			Serial:      "ZFL1ABCD",
+			Size:        2000 * gib,
+			Type:        "HDD",
+			Health:      true,
+			SmartStatus: "PASSED",
+			Temperature: 36,
+			Children:    []partition{},
+		},
+	}
+
+	s.merges = nil
+	s.nextMergeID = 1
+
+	s.storages = []*storage{
+		{
+			Name:       "Storage1",
+			Path:       "/dev/sdb1",
+			MountPoint: "/media/Storage1",
+			Disk:       "/dev/sdb",
+			Type:       "ext4",
+			Size:       4000 * gib,
+			Used:       1200 * gib,
+			Avail:      2800 * gib,
+		},
+	}
+}
+
+func (s *Server) registerStorageRoutes() {
+	s.handle(http.MethodGet, "/v1/disks", s.listDisks)
+	s.handle(http.MethodGet, "/v1/storage", s.listStorages)
+	s.handle(http.MethodPost, "/v1/storage", s.createStorage)
+	s.handle(http.MethodDelete, "/v1/storage", s.deleteStorage)
+
+	s.handle(http.MethodGet, "/v2/local_storage/merges", s.listMerges)
+	s.handle(http.MethodPost, "/v2/local_storage/merges", s.createMerge)
+	s.handle(http.MethodPut, "/v2/local_storage/merges/{id}", s.updateMerge)
+	s.handle(http.MethodDelete, "/v2/local_storage/merges/{id}", s.deleteMerge)
+}
+
+// UnmountStorage unmounts the storage at mountPoint behind the provider's
+// back, as if it had been removed from the dashboard.
+func (s *Server) UnmountStorage(mountPoint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unmount(mountPoint)
+}
+
+// RenameDisk gives the disk at oldPath the device path newPath, as when the
+// disks are renumbered after a reboot. Its partitions and storages follow.
+func (s *Server) RenameDisk(oldPath, newPath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range s.disks {
+		if d.Path != oldPath {
+			continue
+		}
+
+		d.Path = newPath
+
+		for i := range d.Children {
+			d.Children[i].Path = newPath + strings.TrimPrefix(d.Children[i].Path, oldPath)
+		}
+	}
+
+	for _, st := range s.storages {
+		if st.Disk == oldPath {
+			st.Disk = newPath
+			st.Path = newPath + strings.TrimPrefix(st.Path, oldPath)
+		}
+	}
+}
+
+func (s *Server) listDisks(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advanceFormats()
+
+	writeData(w, r, s.disks)
+}
+
+func (s *Server) listStorages(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advanceFormats()
+
+	writeData(w, r, s.storages)
+}
+
+// createStorage wipes a disk, formats it with a single partition and mounts
+// that as a storage. The format finishes after a few listings.
+func (s *Server) createStorage(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req struct {
+		Path   string `json:"path"`
+		Name   string `json:"name"`
+		FSType string `json:"fstype"`
+		Format bool   `json:"format"`
+	}
+
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.disk(req.Path)
+
+	switch {
+	case d == nil:
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("disk %s not found", req.Path))
+		return
+	case !req.Format:
+		writeError(w, r, http.StatusBadRequest, "creating a storage formats the disk, format must be true")
+		return
+	case !filesystems[req.FSType]:
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("unsupported filesystem %q", req.FSType))
+		return
+	case req.Name == "":
+		writeError(w, r, http.StatusBadRequest, "missing name")
+		return
+	case d.State != "":
+		writeError(w, r, http.StatusConflict, fmt.Sprintf("disk %s is busy: %s", d.Path, d.State))
+		return
+	}
+
+	for _, p := range d.Children {
+		if p.MountPoint == "/" {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("disk %s holds the system partition", d.Path))
+			return
+		}
+	}
+
+	mountPoint := path.Join("/media", req.Name)
+
+	for _, st := range s.storages {
+		if st.MountPoint == mountPoint && st.Disk != d.Path {
+			writeError(w, r, http.StatusConflict, fmt.Sprintf("a storage is already mounted at %s", mountPoint))
+			return
+		}
+	}
+
+	for _, p := range d.Children {
+		if p.MountPoint != "" {
+			s.unmount(p.MountPoint)
+		}
+	}
+
+	d.Children = []partition{}
+	d.State = "formatting"
+	d.formatPolls = formatPolls
+	d.format = &storage{
+		Name:       req.Name,
+		Path:       d.Path + "1",
+		MountPoint: mountPoint,
+		Disk:       d.Path,
+		Type:       req.FSType,
+		Size:       d.Size,
+		Avail:      d.Size,
+	}
+
+	writeData(w, r, map[string]string{"state": d.State})
+}
+
+func (s *Server) deleteStorage(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	mountPoint := r.URL.Query().Get("mount_point")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.unmount(mountPoint) {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("no storage mounted at %q", mountPoint))
+		return
+	}
+
+	writeData(w, r, nil)
+}
+
+func (s *Server) listMerges(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The capacity of a pool is that of the members still mounted.
+	for _, m := range s.merges {
+		m.Size, m.Used, m.Avail = 0, 0, 0
+
+		for _, source := range m.SourceVolumePaths {
+			if st := s.storageAt(source); st != nil {
+				m.Size += st.Size
+				m.Used += st.Used
+				m.Avail += st.Avail
+			}
+		}
+	}
+
+	writeData(w, r, s.merges)
+}
+
+func (s *Server) createMerge(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req mergeRequest
+
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.validMerge(w, r, 0, req) {
+		return
+	}
+
+	m := &merge{
+		ID:                s.nextMergeID,
+		FSType:            "fuse.mergerfs",
+		MountPoint:        req.MountPoint,
+		SourceVolumePaths: req.SourceVolumePaths,
+	}
+
+	s.nextMergeID++
+	s.merges = append(s.merges, m)
+
+	writeData(w, r, m)
+}
+
+func (s *Server) updateMerge(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var req mergeRequest
+
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.merge(params["id"])
+	if m == nil {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("merge %s not found", params["id"]))
+		return
+	}
+
+	if !s.validMerge(w, r, m.ID, req) {
+		return
+	}
+
+	m.MountPoint = req.MountPoint
+	m.SourceVolumePaths = req.SourceVolumePaths
+
+	writeData(w, r, m)
+}
+
+func (s *Server) deleteMerge(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.merges {
+		if strconv.FormatInt(m.ID, 10) == params["id"] {
+			s.merges = append(s.merges[:i], s.merges[i+1:]...)
+			writeData(w, r, nil)
+
+			return
+		}
+	}
+
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("merge %s not found", params["id"]))
+}
+
+// validMerge checks req for the merge with the given ID, 0 for a new one,
+// and answers HTTP 400 or 409 when it is not valid. s.mu must be held.
+func (s *Server) validMerge(w http.ResponseWriter, r *http.Request, id int64, req mergeRequest) bool {
+	if !strings.HasPrefix(req.MountPoint, "/") || len(req.SourceVolumePaths) == 0 {
+		writeError(w, r, http.StatusBadRequest, "a merge needs an absolute mount point and at least one source volume")
+		return false
+	}
+
+	for _, source := range req.SourceVolumePaths {
+		if s.storageAt(source) == nil {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("source volume %s is not a mounted storage", source))
+			return false
+		}
+	}
+
+	for _, m := range s.merges {
+		if m.ID != id && m.MountPoint == req.MountPoint {
+			writeError(w, r, http.StatusConflict, fmt.Sprintf("a merge is already mounted at %s", req.MountPoint))
+			return false
+		}
+	}
+
+	return true
+}
+
+// advanceFormats moves every formatting disk one listing closer to its
+// storage being mounted. s.mu must be held.
+func (s *Server) advanceFormats() {
+	for _, d := range s.disks {
+		if d.format == nil {
+			continue
+		}
+
+		d.formatPolls--
+
+		if d.formatPolls > 0 {
+			continue
+		}
+
+		d.Children = []partition{
+			{Path: d.format.Path, Size: d.format.Size, FSType: d.format.Type, MountPoint: d.format.MountPoint},
+		}
+		d.State = ""
+		s.storages = append(s.storages, d.format)
+		d.format = nil
+	}
+}
+
+// unmount removes the storage at mountPoint and reports whether there was
+// one. The partition and its data stay on the disk. s.mu must be held.
+func (s *Server) unmount(mountPoint string) bool {
+	for i, st := range s.storages {
+		if st.MountPoint != mountPoint {
+			continue
+		}
+
+		s.storages = append(s.storages[:i], s.storages[i+1:]...)
+
+		for _, d := range s.disks {
+			for j := range d.Children {
+				if d.Children[j].MountPoint == mountPoint {
+					d.Children[j].MountPoint = ""
+				}
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// storageAt returns the storage mounted at mountPoint, or nil. s.mu must be
+// held.
+func (s *Server) storageAt(mountPoint string) *storage {
+	for _, st := range s.storages {
+		if st.MountPoint == mountPoint {
+			return st
+		}
+	}
+
+	return nil
+}
+
+// merge returns the merge with the given ID, or nil. s.mu must be held.
+func (s *Server) merge(id string) *merge {
+	for _, m := range s.merges {
+		if strconv.FormatInt(m.ID, 10) == id {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// disk returns the disk at devicePath, or nil. s.mu must be held.
+func (s *Server) disk(devicePath string) *disk {
+	for _, d := range s.disks {
+		if d.Path == devicePath {
+			return d
+		}
+	}
+
+	return nil
+}
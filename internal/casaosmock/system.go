@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"net/http"
+)
+
+// systemInfo describes the device.
+type systemInfo struct {
+	version    string
+	hostname   string
+	kernel     string
+	arch       string
+	cpuModel   string
+	cpuCores   int
+	memTotal   uint64
+	memUsed    uint64
+	uptime     int64
+	interfaces []networkInterface
+}
+
+// networkInterface is a network interface of the device.
+type networkInterface struct {
+	Name string   `json:"name"`
+	IPs  []string `json:"ips"`
+}
+
+func (s *Server) seedSystem() {
+	s.system = systemInfo{
+		version:  "0.4.15",
+		hostname: "casaos",
+		kernel:   "6.1.0-18-amd64",
+		arch:     "amd64",
+		cpuModel: "Intel(R) Celeron(R) J4125 CPU @ 2.00GHz",
+		cpuCores: 4,
+		memTotal: 8 * gib,
+		memUsed:  3 * gib,
+		uptime:   86400,
+		interfaces: []networkInterface{
+			{Name: "eth0", IPs: []string{"192.168.1.20/24", "fe80::1/64"}},
+			{Name: "docker0", IPs: []string{"172.17.0.1/16"}},
+		},
+	}
+}
+
+func (s *Server) registerSystemRoutes() {
+	s.handle(http.MethodGet, "/v1/sys/version", s.getVersion)
+	s.handle(http.MethodGet, "/v1/sys/hardware", s.getHardware)
+	s.handle(http.MethodGet, "/v1/sys/utilization", s.getUtilization)
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, r, map[string]interface{}{
+		"current_version": s.system.version,
+		"need_update":     false,
+	})
+}
+
+func (s *Server) getHardware(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, r, map[string]interface{}{
+		"arch":     s.system.arch,
+		"hostname": s.system.hostname,
+		"kernel":   s.system.kernel,
+		"uptime":   s.system.uptime,
+	})
+}
+
+func (s *Server) getUtilization(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, r, map[string]interface{}{
+		"cpu": map[string]interface{}{
+			"model":   s.system.cpuModel,
+			"num":     s.system.cpuCores,
+			"percent": 7.5,
+		},
+		"mem": map[string]interface{}{
+			"total":       s.system.memTotal,
+			"used":        s.system.memUsed,
+			"available":   s.system.memTotal - s.system.memUsed,
+			"usedPercent": float64(s.system.memUsed) / float64(s.system.memTotal) * 100,
+		},
+		"net": s.system.interfaces,
+	})
+}
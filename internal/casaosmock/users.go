@@ -0,0 +1,337 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type tokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+// user is a CasaOS account. The first account registered is the owner, the
+// mock serves the owner as the current user of every token.
+type user struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Password string `json:"-"`
+	Role     string `json:"role"`
+	Avatar   string `json:"avatar"`
+}
+
+func (s *Server) seedUsers() {
+	s.users = []*user{{
+		ID:       1,
+		Username: Username,
+		Password: Password,
+		Role:     "admin",
+	}}
+	s.registrationKey = "6f1d5c2e-registration-key"
+	s.customData = map[string]json.RawMessage{}
+}
+
+func (s *Server) registerUserRoutes() {
+	s.handlePublic(http.MethodPost, "/v1/users/login", s.login)
+	s.handlePublic(http.MethodPost, "/v1/users/refresh", s.refresh)
+	s.handlePublic(http.MethodGet, "/v1/users/status", s.userStatus)
+	s.handlePublic(http.MethodPost, "/v1/users/register", s.register)
+	s.handle(http.MethodGet, "/v1/users/current", s.currentUser)
+	s.handle(http.MethodPut, "/v1/users/current", s.updateCurrentUser)
+	s.handle(http.MethodPut, "/v1/users/current/password", s.changePassword)
+	s.handle(http.MethodGet, "/v1/users/current/custom/{key}", s.getCustomData)
+	s.handle(http.MethodPut, "/v1/users/current/custom/{key}", s.putCustomData)
+	s.handle(http.MethodDelete, "/v1/users/current/custom/{key}", s.deleteCustomData)
+}
+
+// CustomData returns the JSON document the owner stored under key, or ""
+// when there is none.
+func (s *Server) CustomData(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return string(s.customData[key])
+}
+
+// SetCustomData stores the JSON document value under key for the owner
+// behind the provider's back, as the dashboard does when settings change.
+func (s *Server) SetCustomData(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.customData[key] = json.RawMessage(value)
+}
+
+// RemoveUsers puts the server into the state of a fresh install: no account
+// exists, and the owner has to be registered before anyone can log in.
+func (s *Server) RemoveUsers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = nil
+	s.accessTokens = map[string]time.Time{}
+	s.refreshTokens = map[string]bool{}
+}
+
+// SetAvatar changes the avatar of the owner behind the provider's back.
+func (s *Server) SetAvatar(avatar string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.users) > 0 {
+		s.users[0].Avatar = avatar
+	}
+}
+
+// owner returns the owner account, or nil before it is registered. It must
+// be called with mu held.
+func (s *Server) owner() *user {
+	if len(s.users) == 0 {
+		return nil
+	}
+
+	return s.users[0]
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+
+	var account *user
+
+	for _, u := range s.users {
+		if u.Username == in.Username && u.Password == in.Password {
+			account = u
+		}
+	}
+
+	s.mu.Unlock()
+
+	if account == nil {
+		writeError(w, r, http.StatusUnauthorized, "invalid username or password")
+		return
+	}
+
+	writeData(w, r, map[string]interface{}{
+		"token": s.issueTokens(),
+		"user":  account,
+	})
+}
+
+func (s *Server) userStatus(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, r, map[string]interface{}{
+		"initialized": s.owner() != nil,
+		"key":         s.registrationKey,
+	})
+}
+
+func (s *Server) register(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Key      string `json:"key"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.owner() != nil:
+		writeError(w, r, http.StatusBadRequest, "user already exists")
+		return
+	case in.Key != s.registrationKey:
+		writeError(w, r, http.StatusBadRequest, "key is invalid")
+		return
+	case in.Username == "" || len(in.Password) < 5:
+		writeError(w, r, http.StatusBadRequest, "username is required and password must have at least 5 characters")
+		return
+	}
+
+	s.users = append(s.users, &user{
+		ID:       1,
+		Username: in.Username,
+		Password: in.Password,
+		Role:     "admin",
+	})
+
+	writeData(w, r, s.owner())
+}
+
+func (s *Server) currentUser(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, r, s.owner())
+}
+
+func (s *Server) updateCurrentUser(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		Username *string `json:"username"`
+		Avatar   *string `json:"avatar"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := s.owner()
+
+	if in.Username != nil {
+		if *in.Username == "" {
+			writeError(w, r, http.StatusBadRequest, "username is required")
+			return
+		}
+
+		owner.Username = *in.Username
+	}
+
+	if in.Avatar != nil {
+		owner.Avatar = *in.Avatar
+	}
+
+	writeData(w, r, owner)
+}
+
+func (s *Server) changePassword(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		OldPassword string `json:"old_password"`
+		Password    string `json:"password"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := s.owner()
+
+	switch {
+	case in.OldPassword != owner.Password:
+		writeError(w, r, http.StatusBadRequest, "old password is wrong")
+		return
+	case len(in.Password) < 5:
+		writeError(w, r, http.StatusBadRequest, "password must have at least 5 characters")
+		return
+	}
+
+	owner.Password = in.Password
+
+	writeData(w, r, owner)
+}
+
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var in struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if !readJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	valid := s.refreshTokens[in.RefreshToken]
+	delete(s.refreshTokens, in.RefreshToken)
+	s.mu.Unlock()
+
+	if !valid {
+		writeError(w, r, http.StatusUnauthorized, "refresh token invalid")
+		return
+	}
+
+	writeData(w, r, s.issueTokens())
+}
+
+// issueTokens hands out a new token pair.
+func (s *Server) issueTokens() tokenPair {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextToken++
+
+	pair := tokenPair{
+		AccessToken:  fmt.Sprintf("access-%d", s.nextToken),
+		RefreshToken: fmt.Sprintf("refresh-%d", s.nextToken),
+	}
+
+	expiresAt := time.Now().Add(s.tokenLifetime)
+
+	s.accessTokens[pair.AccessToken] = expiresAt
+	s.refreshTokens[pair.RefreshToken] = true
+	pair.ExpiresAt = expiresAt.Unix()
+
+	return pair
+}
+
+// authorized reports whether r carries a valid access token. Like CasaOS the
+// token is sent without an authentication scheme.
+func (s *Server) authorized(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.accessTokens[r.Header.Get("Authorization")]
+
+	return ok && time.Now().Before(expiresAt)
+}
+
+func (s *Server) getCustomData(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.customData[params["key"]]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("no custom data stored under %q", params["key"]))
+		return
+	}
+
+	writeData(w, r, value)
+}
+
+func (s *Server) putCustomData(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var value json.RawMessage
+
+	if !readJSON(w, r, &value) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.customData[params["key"]] = value
+
+	writeData(w, r, value)
+}
+
+func (s *Server) deleteCustomData(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.customData, params["key"])
+
+	writeData(w, r, nil)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package casaosmock
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// Keys of the custom data of the owner that the dashboard keeps its app grid
+// in.
+const (
+	appOrderKey  = "app_order"
+	appHiddenKey = "app_hidden"
+	appLinksKey  = "app_links"
+)
+
+// widgetSettingsKey is the key of the custom data of the owner that the
+// dashboard keeps its widget settings in.
+const widgetSettingsKey = "widget_settings"
+
+// defaultWidgets are the dashboard widgets of the device in their default
+// order.
+var defaultWidgets = []widget{
+	{Name: "clock", Title: "Clock", Enabled: true},
+	{Name: "system_status", Title: "System Status", Enabled: true},
+	{Name: "storage", Title: "Storage", Enabled: true},
+	{Name: "network", Title: "Network", Enabled: true},
+	{Name: "app_store_hints", Title: "App Store Hints", Enabled: true},
+}
+
+// widget is a dashboard widget and whether the owner shows it.
+type widget struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	Enabled bool   `json:"enabled"`
+}
+
+// appGridTile is an app or external link shown on the dashboard app grid.
+type appGridTile struct {
+	Name   string `json:"name"`
+	Title  string `json:"title"`
+	Type   string `json:"type"`
+	Hidden bool   `json:"hidden"`
+}
+
+func (s *Server) registerWebRoutes() {
+	s.handle(http.MethodGet, "/v2/casaos/web/appgrid", s.getAppGrid)
+	s.handle(http.MethodGet, "/v2/casaos/web/widgets", s.getWidgets)
+}
+
+// getAppGrid lists the installed apps and the external links in the order
+// stored in the custom data of the owner. Tiles missing from the stored
+// order follow, apps first, each sorted by name.
+func (s *Server) getAppGrid(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		order  []string
+		hidden []string
+		links  []struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		}
+	)
+
+	// Documents the dashboard cannot parse are ignored, as the dashboard
+	// does.
+	_ = json.Unmarshal(s.customData[appOrderKey], &order)
+	_ = json.Unmarshal(s.customData[appHiddenKey], &hidden)
+	_ = json.Unmarshal(s.customData[appLinksKey], &links)
+
+	var tiles []appGridTile
+
+	names := make([]string, 0, len(s.apps))
+
+	for name := range s.apps {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		tiles = append(tiles, appGridTile{Name: name, Title: name, Type: "app"})
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].ID < links[j].ID
+	})
+
+	for _, link := range links {
+		tiles = append(tiles, appGridTile{Name: link.ID, Title: link.Title, Type: "link"})
+	}
+
+	position := map[string]int{}
+
+	for i, name := range order {
+		if _, ok := position[name]; !ok {
+			position[name] = i
+		}
+	}
+
+	sort.SliceStable(tiles, func(i, j int) bool {
+		pi, iok := position[tiles[i].Name]
+		pj, jok := position[tiles[j].Name]
+
+		switch {
+		case iok && jok:
+			return pi < pj
+		default:
+			return iok && !jok
+		}
+	})
+
+	for i := range tiles {
+		for _, name := range hidden {
+			if tiles[i].Name == name {
+				tiles[i].Hidden = true
+			}
+		}
+	}
+
+	if tiles == nil {
+		tiles = []appGridTile{}
+	}
+
+	writeData(w, r, tiles)
+}
+
+// getWidgets lists the widgets of the device in the order stored in the
+// custom data of the owner. Widgets missing from the stored settings follow
+// in their default order and are enabled, settings for widgets the device
+// does not have are ignored.
+func (s *Server) getWidgets(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var settings []struct {
+		Name string `json:"name"`
+		Show bool   `json:"show"`
+	}
+
+	// Documents the dashboard cannot parse are ignored, as the dashboard
+	// does.
+	_ = json.Unmarshal(s.customData[widgetSettingsKey], &settings)
+
+	known := map[string]widget{}
+
+	for _, dw := range defaultWidgets {
+		known[dw.Name] = dw
+	}
+
+	list := []widget{}
+	listed := map[string]bool{}
+
+	for _, setting := range settings {
+		dw, ok := known[setting.Name]
+		if !ok || listed[setting.Name] {
+			continue
+		}
+
+		dw.Enabled = setting.Show
+		list = append(list, dw)
+		listed[setting.Name] = true
+	}
+
+	for _, dw := range defaultWidgets {
+		if !listed[dw.Name] {
+			list = append(list, dw)
+		}
+	}
+
+	writeData(w, r, list)
+}
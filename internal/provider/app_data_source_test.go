@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAppDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccAppResourceConfig("18080") + testAccAppDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.casaos_app.test", "name", "tf-acc-whoami"),
+					resource.TestCheckResourceAttr("data.casaos_app.test", "image", "traefik/whoami:v1.10"),
+					resource.TestCheckResourceAttr("data.casaos_app.test", "ports.#", "1"),
+					resource.TestCheckResourceAttr("data.casaos_app.test", "ports.0.host_port", "18080"),
+					resource.TestCheckResourceAttrSet("data.casaos_app.test", "status"),
+					resource.TestCheckResourceAttrSet("data.casaos_app.test", "web_ui_url"),
+				),
+			},
+		},
+	})
+}
+
+const testAccAppDataSourceConfig = `
+data "casaos_app" "test" {
+  name = casaos_app.test.name
+}
+`
@@ -0,0 +1,355 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AppGridResource{}
+var _ resource.ResourceWithImportState = &AppGridResource{}
+var _ resource.ResourceWithModifyPlan = &AppGridResource{}
+
+func NewAppGridResource() resource.Resource {
+	return &AppGridResource{}
+}
+
+// AppGridResource defines the resource implementation.
+type AppGridResource struct {
+	client *CasaOSClient
+}
+
+// AppGridResourceModel describes the resource data model.
+type AppGridResourceModel struct {
+	Hidden types.Set    `tfsdk:"hidden"`
+	Id     types.String `tfsdk:"id"`
+	Order  types.List   `tfsdk:"order"`
+}
+
+// appGridID is the ID of the only app grid of a device.
+const appGridID = "app_grid"
+
+func (r *AppGridResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_grid"
+}
+
+func (r *AppGridResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Arranges the app grid on the dashboard of the logged in user: which apps and links come first and which are hidden. " +
+			"Tiles are named by app name, or by link ID for [`casaos_app_link`](app_link.md) tiles.\n\n" +
+			"A device has a single app grid, so declare this resource at most once per device, also across provider aliases. The provider only detects a second one declared with the same provider configuration. " +
+			"Destroying the resource shows every hidden tile again and leaves the order as it is.",
+
+		Attributes: map[string]schema.Attribute{
+			"order": schema.ListAttribute{
+				MarkdownDescription: "Tiles to show first, in this order. Tiles not listed follow in their current order. " +
+					"Moving one of the listed tiles, or another tile in front of them, on the dashboard shows up as a change. Left as it is when not set.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"hidden": schema.SetAttribute{
+				MarkdownDescription: "Tiles to hide from the dashboard. Every other tile is shown. Left as it is when not set.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "App grid identifier, always `app_grid`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AppGridResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan refuses a second app grid for the same device, the two would
+// overwrite each other on every apply.
+func (r *AppGridResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	if !r.client.claimSingleton("casaos_app_grid") {
+		resp.Diagnostics.AddError(
+			"Duplicate App Grid",
+			fmt.Sprintf("More than one casaos_app_grid resource is declared for the CasaOS device at %s. "+
+				"A device has a single app grid, declare the resource only once.", r.client.Endpoint()),
+		)
+	}
+}
+
+func (r *AppGridResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AppGridResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(appGridID)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppGridResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AppGridResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Order.IsNull() {
+		order, err := r.client.AppOrder(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the app grid order, got error: %s", err))
+			return
+		}
+
+		// Only the leading tiles are managed, as many as were configured.
+		if n := len(data.Order.Elements()); len(order) > n {
+			order = order[:n]
+		}
+
+		value, diags := types.ListValueFrom(ctx, types.StringType, order)
+
+		resp.Diagnostics.Append(diags...)
+
+		data.Order = value
+	}
+
+	if !data.Hidden.IsNull() {
+		hidden, err := r.client.HiddenApps(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the hidden apps, got error: %s", err))
+			return
+		}
+
+		value, diags := types.SetValueFrom(ctx, types.StringType, hidden)
+
+		resp.Diagnostics.Append(diags...)
+
+		data.Hidden = value
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(appGridID)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppGridResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AppGridResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppGridResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AppGridResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Hidden.IsNull() {
+		return
+	}
+
+	if err := r.client.SetHiddenApps(ctx, nil); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to show the hidden apps again, got error: %s", err))
+		return
+	}
+}
+
+// ImportState reads the whole order and the hidden tiles, as the imported
+// resource manages both.
+func (r *AppGridResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != appGridID {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("The app grid is imported with the ID %q, got %q.", appGridID, req.ID))
+		return
+	}
+
+	order, err := r.client.AppOrder(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the app grid order, got error: %s", err))
+		return
+	}
+
+	hidden, err := r.client.HiddenApps(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the hidden apps, got error: %s", err))
+		return
+	}
+
+	orderValue, diags := types.ListValueFrom(ctx, types.StringType, order)
+	resp.Diagnostics.Append(diags...)
+
+	hiddenValue, diags := types.SetValueFrom(ctx, types.StringType, hidden)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), appGridID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("order"), orderValue)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("hidden"), hiddenValue)...)
+}
+
+// apply arranges the app grid as data describes. Tiles that are not on the
+// grid are kept, so apps installed later take their place, and reported with
+// a warning.
+func (r *AppGridResource) apply(ctx context.Context, data *AppGridResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	tiles, err := r.client.AppGrid(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read the app grid, got error: %s", err))
+		return diags
+	}
+
+	onGrid := map[string]bool{}
+
+	for _, tile := range tiles {
+		onGrid[tile.Name] = true
+	}
+
+	var order, hidden []string
+
+	diags.Append(data.Order.ElementsAs(ctx, &order, false)...)
+	diags.Append(data.Hidden.ElementsAs(ctx, &hidden, false)...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	var unknown []string
+
+	for _, name := range append(append([]string{}, order...), hidden...) {
+		if !onGrid[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+
+		diags.AddWarning(
+			"Unknown App Grid Tiles",
+			fmt.Sprintf("The app grid has no app or link named %s. They are arranged anyway and take effect once installed.", strings.Join(unknown, ", ")),
+		)
+	}
+
+	if !data.Order.IsNull() {
+		listed := map[string]bool{}
+
+		for _, name := range order {
+			listed[name] = true
+		}
+
+		// The rest of the grid follows in its current order.
+		full := append([]string{}, order...)
+
+		for _, tile := range tiles {
+			if !listed[tile.Name] {
+				full = append(full, tile.Name)
+			}
+		}
+
+		if err := r.client.SetAppOrder(ctx, full); err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to arrange the app grid, got error: %s", err))
+			return diags
+		}
+	}
+
+	if !data.Hidden.IsNull() {
+		sort.Strings(hidden)
+
+		if err := r.client.SetHiddenApps(ctx, hidden); err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to hide apps, got error: %s", err))
+			return diags
+		}
+	}
+
+	tflog.Trace(ctx, "arranged the app grid", map[string]interface{}{
+		"order":  order,
+		"hidden": hidden,
+	})
+
+	return diags
+}
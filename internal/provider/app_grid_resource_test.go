@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccAppGridResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAppGridResourceConfig(`["tf-acc-b", "tf-acc-a"]`, `["tf-acc-c"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_grid.test", "order.#", "2"),
+					resource.TestCheckResourceAttr("casaos_app_grid.test", "order.0", "tf-acc-b"),
+					resource.TestCheckResourceAttr("casaos_app_grid.test", "order.1", "tf-acc-a"),
+					resource.TestCheckTypeSetElemAttr("casaos_app_grid.test", "hidden.*", "tf-acc-c"),
+					resource.TestCheckResourceAttr("casaos_app_grid.test", "id", "app_grid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_app_grid.test",
+				ImportState:       true,
+				ImportStateId:     "app_grid",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccAppGridResourceConfig(`["tf-acc-a", "tf-acc-b"]`, `[]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_grid.test", "order.0", "tf-acc-a"),
+					resource.TestCheckResourceAttr("casaos_app_grid.test", "order.1", "tf-acc-b"),
+					resource.TestCheckResourceAttr("casaos_app_grid.test", "hidden.#", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAppGridResource_InstalledApps(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Tiles not listed keep their place after the listed ones
+			{
+				PreConfig: func() {
+					mock.SetCustomData("app_order", `["tf-acc-old", "tf-acc-whoami"]`)
+				},
+				Config: testAccAppResourceConfig("18080") + `
+resource "casaos_app_grid" "test" {
+  order  = ["tf-acc-first"]
+  hidden = [casaos_app.test.name]
+}
+`,
+				Check: func(*terraform.State) error {
+					if order := mock.CustomData("app_order"); order != `["tf-acc-first","tf-acc-whoami"]` {
+						return fmt.Errorf("expected the installed app to follow the listed tiles, got %s", order)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccAppGridResource_Duplicate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppGridResourceConfig(`["tf-acc-a"]`, `[]`) + `
+resource "casaos_app_grid" "other" {
+  hidden = ["tf-acc-b"]
+}
+`,
+				ExpectError: regexp.MustCompile(`More than one casaos_app_grid resource is declared`),
+			},
+		},
+	})
+}
+
+func TestAccAppGridResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppGridResourceConfig(`["tf-acc-a", "tf-acc-b"]`, `["tf-acc-c"]`),
+			},
+			// Tiles rearranged on the dashboard are put back
+			{
+				PreConfig: func() {
+					mock.SetCustomData("app_order", `["tf-acc-b", "tf-acc-a"]`)
+				},
+				Config:             testAccAppGridResourceConfig(`["tf-acc-a", "tf-acc-b"]`, `["tf-acc-c"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and so are tiles shown again
+			{
+				PreConfig: func() {
+					mock.SetCustomData("app_order", `["tf-acc-a", "tf-acc-b"]`)
+					mock.SetCustomData("app_hidden", `[]`)
+				},
+				Config:             testAccAppGridResourceConfig(`["tf-acc-a", "tf-acc-b"]`, `["tf-acc-c"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccAppGridResourceConfig(order string, hidden string) string {
+	return fmt.Sprintf(`
+resource "casaos_app_grid" "test" {
+  order  = %[1]s
+  hidden = %[2]s
+}
+`, order, hidden)
+}
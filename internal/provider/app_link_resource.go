@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AppLinkResource{}
+var _ resource.ResourceWithImportState = &AppLinkResource{}
+
+func NewAppLinkResource() resource.Resource {
+	return &AppLinkResource{}
+}
+
+// AppLinkResource defines the resource implementation.
+type AppLinkResource struct {
+	client *CasaOSClient
+}
+
+// AppLinkResourceModel describes the resource data model.
+type AppLinkResourceModel struct {
+	Icon         types.String `tfsdk:"icon"`
+	Id           types.String `tfsdk:"id"`
+	OpenInNewTab types.Bool   `tfsdk:"open_in_new_tab"`
+	Title        types.String `tfsdk:"title"`
+	URL          types.String `tfsdk:"url"`
+}
+
+// webURLValidator checks that a URL can be opened by a browser.
+var webURLValidator = stringvalidator.RegexMatches(regexp.MustCompile(`^https?://[^/\s]+`), "must be an http or https URL")
+
+func (r *AppLinkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_link"
+}
+
+func (r *AppLinkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Pins a link to another web UI, such as the router or a printer, as a tile on the dashboard app grid of the logged in user. " +
+			"Use `id` to arrange the tile with [`casaos_app_grid`](app_grid.md).",
+
+		Attributes: map[string]schema.Attribute{
+			"title": schema.StringAttribute{
+				MarkdownDescription: "Title shown below the tile.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "URL the tile opens, for example `http://192.168.1.1`.",
+				Required:            true,
+				Validators: []validator.String{
+					webURLValidator,
+				},
+			},
+			"icon": schema.StringAttribute{
+				MarkdownDescription: "URL of the icon of the tile. The dashboard shows a generic icon when empty, the default.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+				Validators: []validator.String{
+					stringvalidator.Any(stringvalidator.LengthAtMost(0), webURLValidator),
+				},
+			},
+			"open_in_new_tab": schema.BoolAttribute{
+				MarkdownDescription: "Whether the link opens in a new browser tab instead of replacing the dashboard. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Link identifier, the name of the tile on the app grid.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AppLinkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *AppLinkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AppLinkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	link, err := r.client.CreateAppLink(ctx, data.appLink())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create link %q, got error: %s", data.Title.ValueString(), err))
+		return
+	}
+
+	data.Id = types.StringValue(link.ID)
+
+	tflog.Trace(ctx, "created an app grid link", map[string]interface{}{
+		"id":  link.ID,
+		"url": link.URL,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppLinkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AppLinkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	link, err := r.client.AppLink(ctx, data.Id.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "app grid link no longer exists, removing from state", map[string]interface{}{
+			"id": data.Id.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read link %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.Icon = types.StringValue(link.Icon)
+	data.OpenInNewTab = types.BoolValue(link.NewTab)
+	data.Title = types.StringValue(link.Title)
+	data.URL = types.StringValue(link.URL)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppLinkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AppLinkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UpdateAppLink(ctx, data.appLink())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update link %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppLinkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AppLinkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteAppLink(ctx, data.Id.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete link %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+}
+
+func (r *AppLinkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (m *AppLinkResourceModel) appLink() AppLink {
+	return AppLink{
+		ID:     m.Id.ValueString(),
+		Title:  m.Title.ValueString(),
+		URL:    m.URL.ValueString(),
+		Icon:   m.Icon.ValueString(),
+		NewTab: m.OpenInNewTab.ValueBool(),
+	}
+}
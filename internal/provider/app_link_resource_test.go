@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccAppLinkResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAppLinkResourceConfig("Router", "http://192.168.1.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_link.test", "title", "Router"),
+					resource.TestCheckResourceAttr("casaos_app_link.test", "url", "http://192.168.1.1"),
+					resource.TestCheckResourceAttr("casaos_app_link.test", "icon", ""),
+					resource.TestCheckResourceAttr("casaos_app_link.test", "open_in_new_tab", "true"),
+					resource.TestCheckResourceAttrSet("casaos_app_link.test", "id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_app_link.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccAppLinkResourceConfig("Printer", "https://printer.lan") + `
+resource "casaos_app_link" "other" {
+  title           = "Wiki"
+  url             = "https://wiki.example.com"
+  icon            = "https://wiki.example.com/favicon.png"
+  open_in_new_tab = false
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_link.test", "title", "Printer"),
+					resource.TestCheckResourceAttr("casaos_app_link.test", "url", "https://printer.lan"),
+					resource.TestCheckResourceAttr("casaos_app_link.other", "icon", "https://wiki.example.com/favicon.png"),
+					resource.TestCheckResourceAttr("casaos_app_link.other", "open_in_new_tab", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAppLinkResource_Parallel(t *testing.T) {
+	ids := map[string]bool{}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Links created at the same time all end up on the grid
+			{
+				Config: `
+resource "casaos_app_link" "test" {
+  count = 5
+
+  title = "Server ${count.index}"
+  url   = "http://server${count.index}.lan"
+}
+
+resource "casaos_app_grid" "test" {
+  order = reverse(casaos_app_link.test[*].id)
+}
+`,
+				Check: func(s *terraform.State) error {
+					for i := 0; i < 5; i++ {
+						ids[s.RootModule().Resources[fmt.Sprintf("casaos_app_link.test.%d", i)].Primary.ID] = true
+					}
+
+					if len(ids) != 5 {
+						return fmt.Errorf("expected 5 distinct link IDs, got %v", ids)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccAppLinkResource_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAppLinkResourceConfig("Router", "192.168.1.1"),
+				ExpectError: regexp.MustCompile(`must be an http or https URL`),
+			},
+		},
+	})
+}
+
+func TestAccAppLinkResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppLinkResourceConfig("Router", "http://192.168.1.1"),
+			},
+			// A link edited on the dashboard is changed back
+			{
+				PreConfig: func() {
+					links := mock.CustomData("app_links")
+					mock.SetCustomData("app_links", strings.Replace(links, `"title":"Router"`, `"title":"Modem"`, 1))
+				},
+				Config:             testAccAppLinkResourceConfig("Router", "http://192.168.1.1"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and a deleted one created again
+			{
+				PreConfig: func() {
+					mock.SetCustomData("app_links", `[]`)
+				},
+				Config:             testAccAppLinkResourceConfig("Router", "http://192.168.1.1"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAppLinkResource_NewID(t *testing.T) {
+	var first string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppLinkResourceConfig("Router", "http://192.168.1.1"),
+				Check: func(s *terraform.State) error {
+					first = s.RootModule().Resources["casaos_app_link.test"].Primary.ID
+					return nil
+				},
+			},
+			{
+				Config: `locals {}`,
+			},
+			// A link created after a deleted one does not get its ID, which an
+			// app grid may still refer to
+			{
+				Config: testAccAppLinkResourceConfig("Router", "http://192.168.1.1"),
+				Check: func(s *terraform.State) error {
+					if id := s.RootModule().Resources["casaos_app_link.test"].Primary.ID; id == first {
+						return fmt.Errorf("expected a new ID for the new link, got %s again", id)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccAppLinkResourceConfig(title string, url string) string {
+	return fmt.Sprintf(`
+resource "casaos_app_link" "test" {
+  title = %[1]q
+  url   = %[2]q
+}
+`, title, url)
+}
@@ -0,0 +1,442 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AppResource{}
+var _ resource.ResourceWithImportState = &AppResource{}
+var _ resource.ResourceWithModifyPlan = &AppResource{}
+var _ resource.ResourceWithValidateConfig = &AppResource{}
+
+func NewAppResource() resource.Resource {
+	return &AppResource{}
+}
+
+// AppResource defines the resource implementation.
+type AppResource struct {
+	client *CasaOSClient
+}
+
+// AppResourceModel describes the resource data model.
+type AppResourceModel struct {
+	Compose      types.String   `tfsdk:"compose"`
+	Id           types.String   `tfsdk:"id"`
+	KeepData     types.Bool     `tfsdk:"keep_data"`
+	Name         types.String   `tfsdk:"name"`
+	Status       types.String   `tfsdk:"status"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+	WaitForWebUI types.Bool     `tfsdk:"wait_for_web_ui"`
+}
+
+func (r *AppResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app"
+}
+
+func (r *AppResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Installs and manages an app from a Docker Compose file in the CasaOS `x-casaos` extended format.",
+
+		Attributes: map[string]schema.Attribute{
+			"compose": schema.StringAttribute{
+				MarkdownDescription: "Docker Compose YAML of the app. The top-level `name` is used as the app name and the top-level `x-casaos` extension describes the app to the dashboard. Changing the project name replaces the app.",
+				Required:            true,
+			},
+			"keep_data": schema.BoolAttribute{
+				MarkdownDescription: "Whether to keep the app data under `/DATA/AppData` when the app is uninstalled, also when it is replaced. Defaults to `true`, set it to `false` to delete the data together with the app.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"wait_for_web_ui": schema.BoolAttribute{
+				MarkdownDescription: waitForWebUIDescription,
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "App name, taken from the top-level `name` of the compose file.",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Current status of the app, such as `running` or `stopped`.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "App identifier, same as `name`.",
+				Computed:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *AppResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *AppResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AppResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Compose.IsNull() || data.Compose.IsUnknown() {
+		return
+	}
+
+	if _, err := parseComposeHeader(data.Compose.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("compose"), "Invalid Compose File", err.Error())
+	}
+}
+
+func (r *AppResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do when the resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan AppResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.Compose.IsUnknown() {
+		return
+	}
+
+	header, err := parseComposeHeader(plan.Compose.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("compose"), "Invalid Compose File", err.Error())
+		return
+	}
+
+	// The app name is derived from the compose file, so it is known as soon
+	// as the compose file is.
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), header.Name)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), header.Name)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state AppResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// CasaOS cannot rename an installed app.
+	if state.Name.ValueString() != header.Name {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("name"))
+	}
+}
+
+func (r *AppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AppResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	header, err := parseComposeHeader(data.Compose.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("compose"), "Invalid Compose File", err.Error())
+		return
+	}
+
+	if err := r.client.InstallComposeApp(ctx, data.Compose.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to install app %q, got error: %s", header.Name, err))
+		return
+	}
+
+	data.Id = types.StringValue(header.Name)
+	data.Name = types.StringValue(header.Name)
+
+	tflog.Trace(ctx, "installed an app", map[string]interface{}{
+		"name": header.Name,
+	})
+
+	// Save the identifier right away, the app exists even if it never
+	// comes up below.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultAppCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(waitForAppStatus(ctx, r.client, header.Name, "install", AppStatusRunning, data.WaitForWebUI.ValueBool(), createTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := r.client.ComposeAppStatus(ctx, header.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status of app %q, got error: %s", header.Name, err))
+		return
+	}
+
+	data.Status = types.StringValue(status)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AppResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := r.client.ComposeAppStatus(ctx, data.Id.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "app no longer installed, removing from state", map[string]interface{}{
+			"name": data.Id.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.Name = data.Id
+	data.Status = types.StringValue(status)
+
+	// CasaOS rewrites the compose file it stores, so the configured one is
+	// kept as-is and the stored one is only used after an import.
+	if data.Compose.IsNull() {
+		compose, err := r.client.ComposeAppYAML(ctx, data.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read compose file of app %q, got error: %s", data.Id.ValueString(), err))
+			return
+		}
+
+		data.Compose = types.StringValue(compose)
+	}
+
+	if data.KeepData.IsNull() {
+		data.KeepData = types.BoolValue(true)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state AppResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Compose.Equal(state.Compose) {
+		if err := r.client.UpdateComposeApp(ctx, data.Id.ValueString(), data.Compose.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update app %q, got error: %s", data.Id.ValueString(), err))
+			return
+		}
+
+		tflog.Trace(ctx, "updated an app", map[string]interface{}{
+			"name": data.Id.ValueString(),
+		})
+
+		updateTimeout, diags := data.Timeouts.Update(ctx, defaultAppUpdateTimeout)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(waitForAppStatus(ctx, r.client, data.Id.ValueString(), "update", AppStatusRunning, data.WaitForWebUI.ValueBool(), updateTimeout)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	status, err := r.client.ComposeAppStatus(ctx, data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status of app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.Status = types.StringValue(status)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AppResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultAppDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UninstallComposeApp(ctx, data.Id.ValueString(), !data.KeepData.ValueBool())
+	if IsNotFound(err) {
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to uninstall app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(waitForAppUninstalled(ctx, r.client, data.Id.ValueString(), deleteTimeout)...)
+}
+
+func (r *AppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// waitForWebUIDescription documents the wait_for_web_ui attribute of the
+// resources that install apps.
+const waitForWebUIDescription = "Whether create and update also wait for the web UI of the app to answer HTTP requests, instead of only for its containers to run. Has no effect on apps without a web UI port. Defaults to `false`."
+
+// Default timeouts of the resources that install apps. Pulling images on a
+// small device over a slow link easily takes several minutes.
+const (
+	defaultAppCreateTimeout = 20 * time.Minute
+	defaultAppUpdateTimeout = 20 * time.Minute
+	defaultAppDeleteTimeout = 10 * time.Minute
+)
+
+// waitForAppStatus waits until the app reports status want. With waitForWebUI
+// set it then waits for the web UI of the app to answer, when it has one.
+func waitForAppStatus(ctx context.Context, client *CasaOSClient, name, job, want string, waitForWebUI bool, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	op := operation{
+		Description: fmt.Sprintf("%s of app %q", job, name),
+		Poll: func(ctx context.Context) (string, bool, error) {
+			status, err := client.ComposeAppStatus(ctx, name)
+
+			// A freshly installed app only shows up once its compose
+			// project has been created.
+			if IsNotFound(err) {
+				return "", false, nil
+			}
+
+			if err != nil {
+				return "", false, err
+			}
+
+			return status, status == want, nil
+		},
+		Logs: func(ctx context.Context, lines int) (string, error) {
+			return client.ComposeAppLogs(ctx, name, lines)
+		},
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	diags.Append(waitForOperation(ctx, timeout, op)...)
+
+	if diags.HasError() || !waitForWebUI {
+		return diags
+	}
+
+	// The web UI address is only known once the app is installed.
+	app, err := client.ComposeApp(ctx, name)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read app %q, got error: %s", name, err))
+		return diags
+	}
+
+	op.Ready = appWebUIURL(app.StoreInfo, client.Hostname())
+
+	if op.Ready == "" {
+		diags.AddWarning(
+			"App Has No Web UI",
+			fmt.Sprintf("App %q does not declare a web UI port in its x-casaos extension, so wait_for_web_ui has no effect.", name),
+		)
+
+		return diags
+	}
+
+	diags.Append(waitForOperation(ctx, time.Until(deadline), op)...)
+
+	return diags
+}
+
+// waitForAppUninstalled waits until the app is gone from the device.
+func waitForAppUninstalled(ctx context.Context, client *CasaOSClient, name string, timeout time.Duration) diag.Diagnostics {
+	return waitForOperation(ctx, timeout, operation{
+		Description: fmt.Sprintf("uninstall of app %q", name),
+		Poll: func(ctx context.Context) (string, bool, error) {
+			status, err := client.ComposeAppStatus(ctx, name)
+			if IsNotFound(err) {
+				return "uninstalled", true, nil
+			}
+
+			return status, false, err
+		},
+	})
+}
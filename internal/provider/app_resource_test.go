@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccAppResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAppResourceConfig("18080"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app.test", "id", "tf-acc-whoami"),
+					resource.TestCheckResourceAttr("casaos_app.test", "name", "tf-acc-whoami"),
+					resource.TestCheckResourceAttr("casaos_app.test", "keep_data", "true"),
+					resource.TestCheckResourceAttrSet("casaos_app.test", "status"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_app.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// CasaOS rewrites the compose file it stores, so the imported
+				// compose does not match the configured one byte for byte.
+				ImportStateVerifyIgnore: []string{"compose", "status"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccAppResourceConfig("18081"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app.test", "id", "tf-acc-whoami"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAppResource_KeepData(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The app data is only deleted when keep_data is false.
+		CheckDestroy: func(*terraform.State) error {
+			if !mock.Exists("/DATA/AppData/tf-acc-whoami") {
+				return fmt.Errorf("expected the app data to be kept")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppResourceConfig("18080"),
+			},
+		},
+	})
+}
+
+func TestAccAppResource_Faults(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Slow responses and tokens that need refreshing on every request
+			{
+				PreConfig: func() {
+					mock.SetTokenLifetime(time.Minute)
+					mock.AddFault(casaosmock.Fault{Latency: 100 * time.Millisecond})
+				},
+				Config: testAccAppResourceConfig("18080"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app.test", "status", "running"),
+				),
+			},
+			// Tokens expiring between two runs
+			{
+				PreConfig: func() {
+					mock.ClearFaults()
+					mock.ExpireTokens()
+				},
+				Config: testAccAppResourceConfig("18081"),
+			},
+			// Server errors are reported
+			{
+				PreConfig: func() {
+					mock.AddFault(casaosmock.Fault{
+						Method: http.MethodPut,
+						Path:   "/v2/app_management/compose/tf-acc-whoami",
+						Status: http.StatusInternalServerError,
+						Times:  1,
+					})
+				},
+				Config:      testAccAppResourceConfig("18082"),
+				ExpectError: regexp.MustCompile(`HTTP\s+500`),
+			},
+			// and the next apply goes through
+			{
+				Config: testAccAppResourceConfig("18082"),
+			},
+		},
+	})
+}
+
+func testAccAppResourceConfig(port string) string {
+	return fmt.Sprintf(`
+resource "casaos_app" "test" {
+  compose = <<-EOT
+    name: tf-acc-whoami
+    services:
+      whoami:
+        image: traefik/whoami:v1.10
+        ports:
+          - target: 80
+            published: "%[1]s"
+            protocol: tcp
+    x-casaos:
+      main: whoami
+      port_map: "%[1]s"
+      title:
+        en_us: Terraform acceptance test
+  EOT
+}
+`, port)
+}
@@ -0,0 +1,298 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultAppStateTimeout bounds starting or stopping an app.
+const defaultAppStateTimeout = 5 * time.Minute
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AppStateResource{}
+var _ resource.ResourceWithImportState = &AppStateResource{}
+
+func NewAppStateResource() resource.Resource {
+	return &AppStateResource{}
+}
+
+// AppStateResource defines the resource implementation.
+type AppStateResource struct {
+	client *CasaOSClient
+}
+
+// AppStateResourceModel describes the resource data model.
+type AppStateResourceModel struct {
+	Id              types.String   `tfsdk:"id"`
+	Name            types.String   `tfsdk:"name"`
+	RestartTriggers types.Map      `tfsdk:"restart_triggers"`
+	State           types.String   `tfsdk:"state"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *AppStateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_state"
+}
+
+func (r *AppStateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Keeps an installed app running or stopped. An app started or stopped from the dashboard shows up as a change on the next plan. Destroying this resource leaves the app in its current state.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the installed app. Changing this replaces the resource.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "Desired state of the app, `running` or `stopped`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(AppStatusRunning, AppStatusStopped),
+				},
+			},
+			"restart_triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that restart the app when any of them changes, for example the hash of a config file the app reads on startup. Ignored while `state` is `stopped`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "App identifier, same as `name`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+func (r *AppStateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// converge starts or stops the app until it is in the desired state, and
+// restarts a running app when restart is set.
+func (r *AppStateResource) converge(ctx context.Context, name, desired string, restart bool) error {
+	current, err := r.client.ComposeAppStatus(ctx, name)
+	if err != nil {
+		return fmt.Errorf("reading status: %w", err)
+	}
+
+	var action string
+
+	switch {
+	case desired == AppStatusRunning && current != AppStatusRunning:
+		action = AppActionStart
+	case desired == AppStatusRunning && restart:
+		action = AppActionRestart
+	case desired == AppStatusStopped && current != AppStatusStopped:
+		action = AppActionStop
+	default:
+		return nil
+	}
+
+	tflog.Debug(ctx, "changing app status", map[string]interface{}{
+		"name":    name,
+		"current": current,
+		"action":  action,
+	})
+
+	if err := r.client.SetComposeAppStatus(ctx, name, action); err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	return nil
+}
+
+// wait waits until the app reports the desired state.
+func (r *AppStateResource) wait(ctx context.Context, data AppStateResourceModel, timeout time.Duration) diag.Diagnostics {
+	job := "start"
+	if data.State.ValueString() == AppStatusStopped {
+		job = "stop"
+	}
+
+	return waitForAppStatus(ctx, r.client, data.Name.ValueString(), job, data.State.ValueString(), false, timeout)
+}
+
+func (r *AppStateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AppStateResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultAppStateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.converge(ctx, data.Name.ValueString(), data.State.ValueString(), false); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to set app %q %s, got error: %s", data.Name.ValueString(), data.State.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.wait(ctx, data, createTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.Name
+
+	tflog.Trace(ctx, "created an app state", map[string]interface{}{
+		"name":  data.Name.ValueString(),
+		"state": data.State.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AppStateResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := r.client.ComposeAppStatus(ctx, data.Id.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "app no longer installed, removing from state", map[string]interface{}{
+			"name": data.Id.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status of app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.Name = data.Id
+
+	// Record the actual state so an app stopped or started from the
+	// dashboard is planned back. Transient statuses such as updating keep the
+	// prior state.
+	switch status {
+	case AppStatusRunning, AppStatusStopped:
+		data.State = types.StringValue(status)
+	default:
+		tflog.Debug(ctx, "app is in a transient status, keeping prior state", map[string]interface{}{
+			"name":   data.Id.ValueString(),
+			"status": status,
+		})
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state AppStateResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultAppStateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	restart := !data.RestartTriggers.Equal(state.RestartTriggers)
+
+	if err := r.converge(ctx, data.Name.ValueString(), data.State.ValueString(), restart); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to set app %q %s, got error: %s", data.Name.ValueString(), data.State.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.wait(ctx, data, updateTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AppStateResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The app is left as it is, removing the resource only stops enforcing
+	// its state.
+	tflog.Trace(ctx, "deleted an app state", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+}
+
+func (r *AppStateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
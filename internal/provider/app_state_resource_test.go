@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccAppStateResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAppStateResourceConfig("stopped", "one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_state.test", "id", "tf-acc-whoami"),
+					resource.TestCheckResourceAttr("casaos_app_state.test", "name", "tf-acc-whoami"),
+					resource.TestCheckResourceAttr("casaos_app_state.test", "state", "stopped"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "casaos_app_state.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"restart_triggers"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccAppStateResourceConfig("running", "one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_state.test", "state", "running"),
+				),
+			},
+			// Restart on trigger change
+			{
+				Config: testAccAppStateResourceConfig("running", "two"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_state.test", "state", "running"),
+					resource.TestCheckResourceAttr("casaos_app_state.test", "restart_triggers.config", "two"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAppStateResource_DashboardStop(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppStateResourceConfig("running", "one"),
+			},
+			// Stopping the app from the dashboard shows up as drift
+			{
+				PreConfig: func() {
+					mock.SetAppStatus("tf-acc-whoami", "stopped")
+				},
+				Config:             testAccAppStateResourceConfig("running", "one"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and is started again on apply
+			{
+				Config: testAccAppStateResourceConfig("running", "one"),
+				Check: func(*terraform.State) error {
+					if status := mock.AppStatus("tf-acc-whoami"); status != "running" {
+						return fmt.Errorf("expected app to be running, got %q", status)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccAppStateResourceConfig(state, trigger string) string {
+	return testAccAppResourceConfig("18080") + fmt.Sprintf(`
+resource "casaos_app_state" "test" {
+  name  = casaos_app.test.name
+  state = %[1]q
+
+  restart_triggers = {
+    config = %[2]q
+  }
+}
+`, state, trigger)
+}
@@ -0,0 +1,462 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AppStoreAppResource{}
+var _ resource.ResourceWithImportState = &AppStoreAppResource{}
+var _ resource.ResourceWithModifyPlan = &AppStoreAppResource{}
+
+func NewAppStoreAppResource() resource.Resource {
+	return &AppStoreAppResource{}
+}
+
+// AppStoreAppResource defines the resource implementation.
+type AppStoreAppResource struct {
+	client *CasaOSClient
+}
+
+// AppStoreAppResourceModel describes the resource data model.
+type AppStoreAppResourceModel struct {
+	Environment  types.Map      `tfsdk:"environment"`
+	Id           types.String   `tfsdk:"id"`
+	KeepData     types.Bool     `tfsdk:"keep_data"`
+	Name         types.String   `tfsdk:"name"`
+	Ports        types.Map      `tfsdk:"ports"`
+	Status       types.String   `tfsdk:"status"`
+	StoreAppId   types.String   `tfsdk:"store_app_id"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+	Version      types.String   `tfsdk:"version"`
+	Volumes      types.Map      `tfsdk:"volumes"`
+	WaitForWebUI types.Bool     `tfsdk:"wait_for_web_ui"`
+	WebUIPort    types.String   `tfsdk:"web_ui_port"`
+}
+
+func (r *AppStoreAppResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_store_app"
+}
+
+func (r *AppStoreAppResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Installs an app from one of the app stores registered on the device. The catalog compose file is resolved at apply time, so a newer catalog release shows up as a change to `version` and upgrades the app.",
+
+		Attributes: map[string]schema.Attribute{
+			"store_app_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the app in the app store catalog, for example `Jellyfin`. Changing this replaces the app.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ports": schema.MapAttribute{
+				MarkdownDescription: "Published host ports of the main service, keyed by container port.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"volumes": schema.MapAttribute{
+				MarkdownDescription: "Host paths bound into the main service, keyed by container path. Container paths the catalog app does not declare are added as extra bind mounts.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"environment": schema.MapAttribute{
+				MarkdownDescription: "Environment variables set on the main service, merged over the catalog defaults.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"web_ui_port": schema.StringAttribute{
+				MarkdownDescription: "Host port the dashboard links to when the app is opened. Defaults to the catalog value.",
+				Optional:            true,
+			},
+			"keep_data": schema.BoolAttribute{
+				MarkdownDescription: "Whether to keep the app data under `/DATA/AppData` when the app is uninstalled, also when it is replaced. Defaults to `true`, set it to `false` to delete the data together with the app.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"wait_for_web_ui": schema.BoolAttribute{
+				MarkdownDescription: waitForWebUIDescription,
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "App name, taken from the catalog compose file.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Installed app version, the image tag of the main service. Known after apply when the app is installed or changed, and planned as a change when the catalog has a newer release.",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Current status of the app, such as `running` or `stopped`.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "App identifier, same as `name`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *AppStoreAppResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *AppStoreAppResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do when the resource is being destroyed or the provider is
+	// not configured yet.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan AppStoreAppResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.StoreAppId.IsUnknown() {
+		return
+	}
+
+	compose, err := r.client.StoreAppCompose(ctx, plan.StoreAppId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("store_app_id"),
+			"Client Error",
+			fmt.Sprintf("Unable to read app %q from the app store catalog, got error: %s", plan.StoreAppId.ValueString(), err),
+		)
+
+		return
+	}
+
+	version, err := composeAppVersion(compose)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Catalog Compose File", fmt.Sprintf("App %q: %s", plan.StoreAppId.ValueString(), err))
+		return
+	}
+
+	// The catalog is read again at apply and may have a newer release by
+	// then, so the version is only known after apply whenever the app is
+	// installed or changed. A newer catalog release shows up as an in-place
+	// upgrade.
+	if !req.State.Raw.IsNull() {
+		var state AppStoreAppResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if req.Plan.Raw.Equal(req.State.Raw) && version == state.Version.ValueString() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("version"), types.StringUnknown())...)
+
+		return
+	}
+
+	header, err := parseComposeHeader(compose)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Catalog Compose File", fmt.Sprintf("App %q: %s", plan.StoreAppId.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), header.Name)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), header.Name)...)
+}
+
+// resolveCompose fetches the catalog compose file of the app and applies the
+// configured overrides to it.
+func (r *AppStoreAppResource) resolveCompose(ctx context.Context, data *AppStoreAppResourceModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	overrides := composeOverrides{
+		WebUIPort: data.WebUIPort.ValueString(),
+	}
+
+	diags.Append(data.Ports.ElementsAs(ctx, &overrides.Ports, false)...)
+	diags.Append(data.Volumes.ElementsAs(ctx, &overrides.Volumes, false)...)
+	diags.Append(data.Environment.ElementsAs(ctx, &overrides.Environment, false)...)
+
+	if diags.HasError() {
+		return "", diags
+	}
+
+	compose, err := r.client.StoreAppCompose(ctx, data.StoreAppId.ValueString())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read app %q from the app store catalog, got error: %s", data.StoreAppId.ValueString(), err))
+		return "", diags
+	}
+
+	compose, err = applyComposeOverrides(compose, overrides)
+	if err != nil {
+		diags.AddError("Invalid App Overrides", fmt.Sprintf("Unable to apply overrides to app %q: %s", data.StoreAppId.ValueString(), err))
+		return "", diags
+	}
+
+	header, err := parseComposeHeader(compose)
+	if err != nil {
+		diags.AddError("Invalid Catalog Compose File", fmt.Sprintf("App %q: %s", data.StoreAppId.ValueString(), err))
+		return "", diags
+	}
+
+	version, err := composeAppVersion(compose)
+	if err != nil {
+		diags.AddError("Invalid Catalog Compose File", fmt.Sprintf("App %q: %s", data.StoreAppId.ValueString(), err))
+		return "", diags
+	}
+
+	data.Id = types.StringValue(header.Name)
+	data.Name = types.StringValue(header.Name)
+	data.Version = types.StringValue(version)
+
+	return compose, diags
+}
+
+func (r *AppStoreAppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AppStoreAppResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	compose, diags := r.resolveCompose(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.InstallComposeApp(ctx, compose); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to install app %q, got error: %s", data.StoreAppId.ValueString(), err))
+		return
+	}
+
+	tflog.Trace(ctx, "installed an app store app", map[string]interface{}{
+		"store_app_id": data.StoreAppId.ValueString(),
+		"version":      data.Version.ValueString(),
+	})
+
+	// Save the identifier right away, the app exists even if it never
+	// comes up below.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultAppCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(waitForAppStatus(ctx, r.client, data.Id.ValueString(), "install", AppStatusRunning, data.WaitForWebUI.ValueBool(), createTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := r.client.ComposeAppStatus(ctx, data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status of app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.Status = types.StringValue(status)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStoreAppResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AppStoreAppResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	compose, err := r.client.ComposeAppYAML(ctx, data.Id.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "app no longer installed, removing from state", map[string]interface{}{
+			"name": data.Id.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	// An app upgraded from the dashboard reports its new version here.
+	version, err := composeAppVersion(compose)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Compose File", fmt.Sprintf("App %q: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	status, err := r.client.ComposeAppStatus(ctx, data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status of app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.Name = data.Id
+	data.Version = types.StringValue(version)
+	data.Status = types.StringValue(status)
+
+	// Imported apps are assumed to come from the catalog under their name.
+	if data.StoreAppId.IsNull() {
+		data.StoreAppId = data.Id
+	}
+
+	if data.KeepData.IsNull() {
+		data.KeepData = types.BoolValue(true)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStoreAppResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AppStoreAppResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Overrides and upgrades both mean installing a freshly resolved compose
+	// file over the current one.
+	compose, diags := r.resolveCompose(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.UpdateComposeApp(ctx, data.Id.ValueString(), compose); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	tflog.Trace(ctx, "updated an app store app", map[string]interface{}{
+		"store_app_id": data.StoreAppId.ValueString(),
+		"version":      data.Version.ValueString(),
+	})
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultAppUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(waitForAppStatus(ctx, r.client, data.Id.ValueString(), "update", AppStatusRunning, data.WaitForWebUI.ValueBool(), updateTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := r.client.ComposeAppStatus(ctx, data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status of app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.Status = types.StringValue(status)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStoreAppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AppStoreAppResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultAppDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UninstallComposeApp(ctx, data.Id.ValueString(), !data.KeepData.ValueBool())
+	if IsNotFound(err) {
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to uninstall app %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(waitForAppUninstalled(ctx, r.client, data.Id.ValueString(), deleteTimeout)...)
+}
+
+func (r *AppStoreAppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
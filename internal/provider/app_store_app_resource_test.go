@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccAppStoreAppResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAppStoreAppResourceConfig("18384"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store_app.test", "store_app_id", "Syncthing"),
+					resource.TestCheckResourceAttr("casaos_app_store_app.test", "ports.8384", "18384"),
+					resource.TestCheckResourceAttrSet("casaos_app_store_app.test", "name"),
+					resource.TestCheckResourceAttrSet("casaos_app_store_app.test", "version"),
+					resource.TestCheckResourceAttrSet("casaos_app_store_app.test", "status"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_app_store_app.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Overrides are applied to the compose file and cannot be told
+				// apart from catalog values once installed.
+				ImportStateVerifyIgnore: []string{"environment", "ports", "status", "store_app_id", "volumes", "web_ui_port"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccAppStoreAppResourceConfig("18385"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store_app.test", "ports.8384", "18385"),
+					resource.TestCheckResourceAttr("casaos_app_store_app.test", "web_ui_port", "18385"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAppStoreAppResource_Upgrade(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The app data is kept unless keep_data is false.
+		CheckDestroy: func(*terraform.State) error {
+			if !mock.Exists("/DATA/AppData/syncthing") {
+				return fmt.Errorf("expected the app data to be kept")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppStoreAppResourceConfig("18384"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store_app.test", "version", "1.27.2"),
+				),
+			},
+			// A new release in the catalog upgrades the app
+			{
+				PreConfig: func() {
+					mock.ReplaceInCatalog("Syncthing", "linuxserver/syncthing:1.27.2", "linuxserver/syncthing:1.27.3")
+				},
+				Config: testAccAppStoreAppResourceConfig("18384"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store_app.test", "version", "1.27.3"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAppStoreAppResourceConfig(port string) string {
+	return fmt.Sprintf(`
+resource "casaos_app_store_app" "test" {
+  store_app_id = "Syncthing"
+
+  ports = {
+    "8384" = %[1]q
+  }
+
+  environment = {
+    TZ = "UTC"
+  }
+
+  web_ui_port = %[1]q
+}
+`, port)
+}
@@ -0,0 +1,291 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AppStoreCatalogDataSource{}
+
+func NewAppStoreCatalogDataSource() datasource.DataSource {
+	return &AppStoreCatalogDataSource{}
+}
+
+// AppStoreCatalogDataSource defines the data source implementation.
+type AppStoreCatalogDataSource struct {
+	client *CasaOSClient
+}
+
+// AppStoreCatalogDataSourceModel describes the data source data model.
+type AppStoreCatalogDataSourceModel struct {
+	Apps         []catalogAppModel `tfsdk:"apps"`
+	Architecture types.String      `tfsdk:"architecture"`
+	Author       types.String      `tfsdk:"author"`
+	Category     types.String      `tfsdk:"category"`
+	Search       types.String      `tfsdk:"search"`
+}
+
+// catalogAppModel describes an app in an app store catalog.
+type catalogAppModel struct {
+	Architectures []types.String `tfsdk:"architectures"`
+	Author        types.String   `tfsdk:"author"`
+	Category      types.String   `tfsdk:"category"`
+	Description   types.String   `tfsdk:"description"`
+	Icon          types.String   `tfsdk:"icon"`
+	Ports         []types.String `tfsdk:"ports"`
+	StoreAppId    types.String   `tfsdk:"store_app_id"`
+	StoreId       types.Int64    `tfsdk:"store_id"`
+	Tagline       types.String   `tfsdk:"tagline"`
+	Title         types.String   `tfsdk:"title"`
+	Version       types.String   `tfsdk:"version"`
+}
+
+func (d *AppStoreCatalogDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_store_catalog"
+}
+
+func (d *AppStoreCatalogDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists the apps available in the app stores registered on the device. App stores that cannot be reached are reported as warnings and left out of the result.",
+
+		Attributes: map[string]schema.Attribute{
+			"category": schema.StringAttribute{
+				MarkdownDescription: "Only list apps in this category, for example `Media`. Case-insensitive.",
+				Optional:            true,
+			},
+			"architecture": schema.StringAttribute{
+				MarkdownDescription: "Only list apps available for this architecture, for example `arm64`.",
+				Optional:            true,
+			},
+			"author": schema.StringAttribute{
+				MarkdownDescription: "Only list apps by this author or developer. Case-insensitive.",
+				Optional:            true,
+			},
+			"search": schema.StringAttribute{
+				MarkdownDescription: "Only list apps whose ID, title, tagline or description contains this text. Case-insensitive.",
+				Optional:            true,
+			},
+			"apps": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching apps, sorted by store app ID.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"store_app_id": schema.StringAttribute{
+							MarkdownDescription: "ID of the app in the catalog, as used by `casaos_app_store_app`.",
+							Computed:            true,
+						},
+						"store_id": schema.Int64Attribute{
+							MarkdownDescription: "ID of the app store the app comes from.",
+							Computed:            true,
+						},
+						"title": schema.StringAttribute{
+							MarkdownDescription: "English title of the app.",
+							Computed:            true,
+						},
+						"tagline": schema.StringAttribute{
+							MarkdownDescription: "English one-line summary of the app.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "English description of the app.",
+							Computed:            true,
+						},
+						"icon": schema.StringAttribute{
+							MarkdownDescription: "URL of the app icon.",
+							Computed:            true,
+						},
+						"category": schema.StringAttribute{
+							MarkdownDescription: "Catalog category of the app.",
+							Computed:            true,
+						},
+						"author": schema.StringAttribute{
+							MarkdownDescription: "Author of the app store entry.",
+							Computed:            true,
+						},
+						"architectures": schema.ListAttribute{
+							MarkdownDescription: "Architectures the app is available for.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "Catalog version of the app, the image tag of the main service.",
+							Computed:            true,
+						},
+						"ports": schema.ListAttribute{
+							MarkdownDescription: "Host ports the main service publishes by default, which must be free to install the app.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AppStoreCatalogDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AppStoreCatalogDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AppStoreCatalogDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stores, err := d.client.ListAppStores(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list app stores, got error: %s", err))
+		return
+	}
+
+	data.Apps = []catalogAppModel{}
+
+	for _, store := range stores {
+		catalog, err := d.client.ListAppStoreCatalog(ctx, store.ID)
+		if err != nil {
+			// One unreachable store should not hide the apps of the others.
+			resp.Diagnostics.AddWarning(
+				"App Store Unavailable",
+				fmt.Sprintf("Unable to read the catalog of app store %d (%s), its apps are left out. Got error: %s", store.ID, store.URL, err),
+			)
+
+			continue
+		}
+
+		for id, app := range catalog {
+			if !catalogAppMatches(data, id, app) {
+				continue
+			}
+
+			data.Apps = append(data.Apps, newCatalogAppModel(store.ID, id, app))
+		}
+	}
+
+	sort.Slice(data.Apps, func(i, j int) bool {
+		if data.Apps[i].StoreAppId.ValueString() != data.Apps[j].StoreAppId.ValueString() {
+			return data.Apps[i].StoreAppId.ValueString() < data.Apps[j].StoreAppId.ValueString()
+		}
+
+		return data.Apps[i].StoreId.ValueInt64() < data.Apps[j].StoreId.ValueInt64()
+	})
+
+	tflog.Trace(ctx, "read app store catalog", map[string]interface{}{
+		"stores": len(stores),
+		"apps":   len(data.Apps),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// catalogAppMatches reports whether a catalog app passes the filters set in
+// the data source configuration.
+func catalogAppMatches(filters AppStoreCatalogDataSourceModel, id string, app CatalogApp) bool {
+	info := app.StoreInfo
+
+	if category := filters.Category.ValueString(); category != "" && !strings.EqualFold(info.Category, category) {
+		return false
+	}
+
+	if architecture := filters.Architecture.ValueString(); architecture != "" {
+		found := false
+
+		for _, a := range info.Architectures {
+			if strings.EqualFold(a, architecture) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if author := filters.Author.ValueString(); author != "" && !strings.EqualFold(info.Author, author) && !strings.EqualFold(info.Developer, author) {
+		return false
+	}
+
+	if search := strings.ToLower(filters.Search.ValueString()); search != "" {
+		texts := []string{id}
+
+		for _, localized := range []map[string]string{info.Title, info.Tagline, info.Description} {
+			for _, text := range localized {
+				texts = append(texts, text)
+			}
+		}
+
+		for _, text := range texts {
+			if strings.Contains(strings.ToLower(text), search) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func newCatalogAppModel(storeID int64, id string, app CatalogApp) catalogAppModel {
+	main := app.Compose.Services[app.MainService()]
+
+	model := catalogAppModel{
+		Architectures: []types.String{},
+		Author:        types.StringValue(app.StoreInfo.Author),
+		Category:      types.StringValue(app.StoreInfo.Category),
+		Description:   types.StringValue(localizedText(app.StoreInfo.Description)),
+		Icon:          types.StringValue(app.StoreInfo.Icon),
+		Ports:         []types.String{},
+		StoreAppId:    types.StringValue(id),
+		StoreId:       types.Int64Value(storeID),
+		Tagline:       types.StringValue(localizedText(app.StoreInfo.Tagline)),
+		Title:         types.StringValue(localizedText(app.StoreInfo.Title)),
+		Version:       types.StringValue(imageTag(main.Image)),
+	}
+
+	for _, architecture := range app.StoreInfo.Architectures {
+		model.Architectures = append(model.Architectures, types.StringValue(architecture))
+	}
+
+	for _, port := range main.Ports {
+		if port.Published != "" {
+			model.Ports = append(model.Ports, types.StringValue(port.Published))
+		}
+	}
+
+	return model
+}
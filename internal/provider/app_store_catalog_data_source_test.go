@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAppStoreCatalogDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccAppStoreCatalogDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.casaos_app_store_catalog.test", "apps.*", map[string]string{
+						"store_app_id": "Jellyfin",
+						"category":     "Media",
+					}),
+				),
+			},
+		},
+	})
+}
+
+const testAccAppStoreCatalogDataSourceConfig = `
+data "casaos_app_store_catalog" "test" {
+  category = "media"
+  search   = "jellyfin"
+}
+`
+
+func TestCatalogAppMatches(t *testing.T) {
+	app := CatalogApp{
+		StoreInfo: ComposeAppStoreInfo{
+			Title:         map[string]string{"en_us": "Jellyfin"},
+			Tagline:       map[string]string{"en_us": "The Free Software Media System"},
+			Author:        "IceWhaleTech",
+			Developer:     "Jellyfin",
+			Category:      "Media",
+			Architectures: []string{"amd64", "arm64"},
+		},
+	}
+
+	for _, tc := range []struct {
+		filters  AppStoreCatalogDataSourceModel
+		expected bool
+	}{
+		{AppStoreCatalogDataSourceModel{}, true},
+		{AppStoreCatalogDataSourceModel{Category: types.StringValue("media")}, true},
+		{AppStoreCatalogDataSourceModel{Category: types.StringValue("Utilities")}, false},
+		{AppStoreCatalogDataSourceModel{Architecture: types.StringValue("arm64")}, true},
+		{AppStoreCatalogDataSourceModel{Architecture: types.StringValue("arm")}, false},
+		{AppStoreCatalogDataSourceModel{Author: types.StringValue("jellyfin")}, true},
+		{AppStoreCatalogDataSourceModel{Author: types.StringValue("someone")}, false},
+		{AppStoreCatalogDataSourceModel{Search: types.StringValue("media system")}, true},
+		{AppStoreCatalogDataSourceModel{Search: types.StringValue("jelly"), Category: types.StringValue("Media")}, true},
+		{AppStoreCatalogDataSourceModel{Search: types.StringValue("plex")}, false},
+	} {
+		if got := catalogAppMatches(tc.filters, "Jellyfin", app); got != tc.expected {
+			t.Errorf("%+v: expected %t, got %t", tc.filters, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AppStoreResource{}
+var _ resource.ResourceWithImportState = &AppStoreResource{}
+
+func NewAppStoreResource() resource.Resource {
+	return &AppStoreResource{}
+}
+
+// AppStoreResource defines the resource implementation.
+type AppStoreResource struct {
+	client *CasaOSClient
+}
+
+// AppStoreResourceModel describes the resource data model.
+type AppStoreResourceModel struct {
+	AppCount types.Int64  `tfsdk:"app_count"`
+	Id       types.String `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	StoreId  types.Int64  `tfsdk:"store_id"`
+	Url      types.String `tfsdk:"url"`
+}
+
+func (r *AppStoreResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_store"
+}
+
+func (r *AppStoreResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Registers a third-party app store source, whose apps can then be installed with `casaos_app_store_app`.",
+
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				MarkdownDescription: "URL of the app store archive, for example `https://casaos-appstore.paodayag.dev/linuxserver.zip`. Changing this replaces the app store.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the app store as reported by the device.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"store_id": schema.Int64Attribute{
+				MarkdownDescription: "ID the device assigned to the app store.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"app_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of apps in the app store catalog.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "App store identifier, same as `url`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AppStoreResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *AppStoreResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AppStoreResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.RegisterAppStore(ctx, data.Url.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to register app store %q, got error: %s", data.Url.ValueString(), err))
+		return
+	}
+
+	store, err := r.client.AppStoreByURL(ctx, data.Url.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app store %q after registering it, got error: %s", data.Url.ValueString(), err))
+		return
+	}
+
+	data.setAppStore(store)
+
+	tflog.Trace(ctx, "registered an app store", map[string]interface{}{
+		"url":      store.URL,
+		"store_id": store.ID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStoreResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AppStoreResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	store, err := r.client.AppStoreByURL(ctx, data.Url.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "app store no longer registered, removing from state", map[string]interface{}{
+			"url": data.Url.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app store %q, got error: %s", data.Url.ValueString(), err))
+		return
+	}
+
+	data.setAppStore(store)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStoreResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AppStoreResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every configurable attribute requires replacement, so an update only
+	// refreshes the computed ones.
+	store, err := r.client.AppStoreByURL(ctx, data.Url.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app store %q, got error: %s", data.Url.ValueString(), err))
+		return
+	}
+
+	data.setAppStore(store)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppStoreResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AppStoreResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// CasaOS numbers stores by position, so the ID in state may belong to
+	// another store by now.
+	store, err := r.client.AppStoreByURL(ctx, data.Url.ValueString())
+	if IsNotFound(err) {
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app store %q, got error: %s", data.Url.ValueString(), err))
+		return
+	}
+
+	err = r.client.UnregisterAppStore(ctx, store.ID)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to unregister app store %q, got error: %s", data.Url.ValueString(), err))
+		return
+	}
+}
+
+func (r *AppStoreResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("url"), req, resp)
+}
+
+func (m *AppStoreResourceModel) setAppStore(store *AppStore) {
+	m.Id = types.StringValue(store.URL)
+	m.Url = types.StringValue(store.URL)
+	m.Name = types.StringValue(store.Name)
+	m.StoreId = types.Int64Value(store.ID)
+	m.AppCount = types.Int64Value(store.AppCount)
+}
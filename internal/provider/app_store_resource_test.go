@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccAppStoreResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAppStoreResourceConfig("https://casaos-appstore.paodayag.dev/linuxserver.zip"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store.test", "id", "https://casaos-appstore.paodayag.dev/linuxserver.zip"),
+					resource.TestCheckResourceAttrSet("casaos_app_store.test", "store_id"),
+					resource.TestCheckResourceAttrSet("casaos_app_store.test", "app_count"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_app_store.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Replace and Read testing
+			{
+				Config: testAccAppStoreResourceConfig("https://casaos-appstore.paodayag.dev/coolstore.zip"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store.test", "url", "https://casaos-appstore.paodayag.dev/coolstore.zip"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAppStoreResource_Renumbered(t *testing.T) {
+	var mock *casaosmock.Server
+
+	const (
+		first  = "https://casaos-appstore.paodayag.dev/linuxserver.zip"
+		second = "https://casaos-appstore.paodayag.dev/coolstore.zip"
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// Only the store of the resource is unregistered, not the one that
+		// took over its old ID.
+		CheckDestroy: func(*terraform.State) error {
+			if mock.AppStoreRegistered(second) {
+				return fmt.Errorf("expected app store %s to be unregistered", second)
+			}
+
+			if !mock.AppStoreRegistered(casaosmock.OfficialAppStoreURL) {
+				return fmt.Errorf("expected the official app store to stay registered")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppStoreResourceConfig(first) + fmt.Sprintf(`
+resource "casaos_app_store" "second" {
+  url = %[1]q
+
+  depends_on = [casaos_app_store.test]
+}
+`, second),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store.second", "store_id", "2"),
+				),
+			},
+			// A store removed on the dashboard moves the ones after it up
+			{
+				PreConfig: func() {
+					mock.RemoveAppStore(first)
+				},
+				Config: fmt.Sprintf(`
+resource "casaos_app_store" "second" {
+  url = %[1]q
+}
+`, second),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_app_store.second", "store_id", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAppStoreResourceConfig(url string) string {
+	return fmt.Sprintf(`
+resource "casaos_app_store" "test" {
+  url = %[1]q
+}
+`, url)
+}
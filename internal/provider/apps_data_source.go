@@ -0,0 +1,305 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AppsDataSource{}
+
+func NewAppsDataSource() datasource.DataSource {
+	return &AppsDataSource{}
+}
+
+// AppsDataSource defines the data source implementation.
+type AppsDataSource struct {
+	client *CasaOSClient
+}
+
+// AppsDataSourceModel describes the data source data model.
+type AppsDataSourceModel struct {
+	Apps []appDataModel `tfsdk:"apps"`
+}
+
+// appDataModel describes an installed app, shared by the casaos_apps and
+// casaos_app data sources.
+type appDataModel struct {
+	ContainerIds []types.String `tfsdk:"container_ids"`
+	Icon         types.String   `tfsdk:"icon"`
+	Image        types.String   `tfsdk:"image"`
+	Name         types.String   `tfsdk:"name"`
+	Ports        []appPortModel `tfsdk:"ports"`
+	Status       types.String   `tfsdk:"status"`
+	StoreAppId   types.String   `tfsdk:"store_app_id"`
+	Title        types.String   `tfsdk:"title"`
+	Version      types.String   `tfsdk:"version"`
+	WebUIURL     types.String   `tfsdk:"web_ui_url"`
+}
+
+// appPortModel describes a published port of an installed app.
+type appPortModel struct {
+	ContainerPort types.Int64  `tfsdk:"container_port"`
+	HostPort      types.String `tfsdk:"host_port"`
+	Protocol      types.String `tfsdk:"protocol"`
+}
+
+func (d *AppsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_apps"
+}
+
+func (d *AppsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists every app installed on the device.",
+
+		Attributes: map[string]schema.Attribute{
+			"apps": schema.ListNestedAttribute{
+				MarkdownDescription: "Installed apps, sorted by name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: appDataAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// appDataAttributes returns the schema of appDataModel with every attribute
+// computed.
+func appDataAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "App name.",
+			Computed:            true,
+		},
+		"title": schema.StringAttribute{
+			MarkdownDescription: "English title shown on the dashboard.",
+			Computed:            true,
+		},
+		"store_app_id": schema.StringAttribute{
+			MarkdownDescription: "ID of the app in the app store catalog, empty for apps installed from a custom compose file.",
+			Computed:            true,
+		},
+		"status": schema.StringAttribute{
+			MarkdownDescription: "App status: `running`, `stopped` or `updating`.",
+			Computed:            true,
+		},
+		"image": schema.StringAttribute{
+			MarkdownDescription: "Docker image of the main service.",
+			Computed:            true,
+		},
+		"version": schema.StringAttribute{
+			MarkdownDescription: "App version, the image tag of the main service.",
+			Computed:            true,
+		},
+		"icon": schema.StringAttribute{
+			MarkdownDescription: "URL of the app icon.",
+			Computed:            true,
+		},
+		"web_ui_url": schema.StringAttribute{
+			MarkdownDescription: "URL the dashboard opens for the app, empty when the app has no web UI.",
+			Computed:            true,
+		},
+		"ports": schema.ListNestedAttribute{
+			MarkdownDescription: "Ports published by the main service.",
+			Computed:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"container_port": schema.Int64Attribute{
+						MarkdownDescription: "Port inside the container.",
+						Computed:            true,
+					},
+					"host_port": schema.StringAttribute{
+						MarkdownDescription: "Port published on the device.",
+						Computed:            true,
+					},
+					"protocol": schema.StringAttribute{
+						MarkdownDescription: "Port protocol, `tcp` or `udp`.",
+						Computed:            true,
+					},
+				},
+			},
+		},
+		"container_ids": schema.ListAttribute{
+			MarkdownDescription: "IDs of the Docker containers of the app.",
+			ElementType:         types.StringType,
+			Computed:            true,
+		},
+	}
+}
+
+func (d *AppsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AppsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AppsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apps, err := d.client.ListComposeApps(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list apps, got error: %s", err))
+		return
+	}
+
+	names := make([]string, 0, len(apps))
+
+	for name := range apps {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	data.Apps = make([]appDataModel, 0, len(names))
+
+	for _, name := range names {
+		containers, err := d.client.ComposeAppContainers(ctx, name)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list containers of app %q, got error: %s", name, err))
+			return
+		}
+
+		data.Apps = append(data.Apps, newAppDataModel(apps[name], containers, d.client.Hostname()))
+	}
+
+	tflog.Trace(ctx, "read installed apps", map[string]interface{}{
+		"count": len(data.Apps),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// newAppDataModel flattens an installed app into appDataModel. Relative web UI
+// URLs are resolved against host, the device the provider talks to.
+func newAppDataModel(app ComposeApp, containers *ComposeAppContainers, host string) appDataModel {
+	main := app.Compose.Services[app.MainService()]
+
+	model := appDataModel{
+		ContainerIds: []types.String{},
+		Icon:         types.StringValue(app.StoreInfo.Icon),
+		Image:        types.StringValue(main.Image),
+		Name:         types.StringValue(app.Name),
+		Ports:        []appPortModel{},
+		Status:       types.StringValue(app.Status),
+		StoreAppId:   types.StringValue(app.StoreInfo.StoreAppID),
+		Title:        types.StringValue(localizedText(app.StoreInfo.Title)),
+		Version:      types.StringValue(imageTag(main.Image)),
+		WebUIURL:     types.StringValue(appWebUIURL(app.StoreInfo, host)),
+	}
+
+	for _, port := range main.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+
+		model.Ports = append(model.Ports, appPortModel{
+			ContainerPort: types.Int64Value(port.Target),
+			HostPort:      types.StringValue(port.Published),
+			Protocol:      types.StringValue(protocol),
+		})
+	}
+
+	services := make([]string, 0, len(containers.Containers))
+
+	for service := range containers.Containers {
+		services = append(services, service)
+	}
+
+	sort.Strings(services)
+
+	for _, service := range services {
+		model.ContainerIds = append(model.ContainerIds, types.StringValue(containers.Containers[service].ID))
+	}
+
+	return model
+}
+
+// appWebUIURL builds the URL the dashboard opens for an app, or "" when the
+// app does not declare a web UI port.
+func appWebUIURL(info ComposeAppStoreInfo, host string) string {
+	if info.PortMap == "" {
+		return ""
+	}
+
+	scheme := info.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	if info.Hostname != "" {
+		host = info.Hostname
+	}
+
+	index := info.Index
+	if index != "" && !strings.HasPrefix(index, "/") {
+		index = "/" + index
+	}
+
+	// Leave out the port when it is the default for the scheme, the way the
+	// dashboard does.
+	if (scheme == "http" && info.PortMap == "80") || (scheme == "https" && info.PortMap == "443") {
+		return scheme + "://" + host + index
+	}
+
+	return scheme + "://" + net.JoinHostPort(host, info.PortMap) + index
+}
+
+// localizedText picks the English variant of a CasaOS localized string,
+// falling back to any other language.
+func localizedText(texts map[string]string) string {
+	for _, locale := range []string{"en_us", "en_US", "en"} {
+		if text, ok := texts[locale]; ok {
+			return text
+		}
+	}
+
+	locales := make([]string, 0, len(texts))
+
+	for locale := range texts {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+
+	if len(locales) == 0 {
+		return ""
+	}
+
+	return texts[locales[0]]
+}
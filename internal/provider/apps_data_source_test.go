@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAppsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccAppResourceConfig("18080") + testAccAppsDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.casaos_apps.test", "apps.*", map[string]string{
+						"name":                   "tf-acc-whoami",
+						"image":                  "traefik/whoami:v1.10",
+						"version":                "v1.10",
+						"ports.0.container_port": "80",
+						"ports.0.host_port":      "18080",
+						"ports.0.protocol":       "tcp",
+						"title":                  "Terraform acceptance test",
+						"container_ids.#":        "1",
+					}),
+				),
+			},
+		},
+	})
+}
+
+const testAccAppsDataSourceConfig = `
+data "casaos_apps" "test" {
+  depends_on = [casaos_app.test]
+}
+`
+
+func TestAppWebUIURL(t *testing.T) {
+	for _, tc := range []struct {
+		info     ComposeAppStoreInfo
+		expected string
+	}{
+		{ComposeAppStoreInfo{}, ""},
+		{ComposeAppStoreInfo{PortMap: "8080"}, "http://casaos.local:8080"},
+		{ComposeAppStoreInfo{PortMap: "80", Index: "admin"}, "http://casaos.local/admin"},
+		{ComposeAppStoreInfo{PortMap: "443", Scheme: "https", Index: "/"}, "https://casaos.local/"},
+		{ComposeAppStoreInfo{PortMap: "8443", Scheme: "https", Hostname: "nas.example.com"}, "https://nas.example.com:8443"},
+	} {
+		if got := appWebUIURL(tc.info, "casaos.local"); got != tc.expected {
+			t.Errorf("%+v: expected %q, got %q", tc.info, tc.expected, got)
+		}
+	}
+}
+
+func TestLocalizedText(t *testing.T) {
+	if got := localizedText(map[string]string{"de_de": "Hallo", "en_us": "Hello"}); got != "Hello" {
+		t.Errorf("expected English text, got %q", got)
+	}
+
+	if got := localizedText(map[string]string{"zh_cn": "你好", "de_de": "Hallo"}); got != "Hallo" {
+		t.Errorf("expected first locale as fallback, got %q", got)
+	}
+
+	if got := localizedText(nil); got != "" {
+		t.Errorf("expected empty text, got %q", got)
+	}
+}
@@ -0,0 +1,524 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenRefreshWindow is how long before expiry an access token is refreshed.
+// Long running operations get a fresh token instead of failing halfway.
+const tokenRefreshWindow = 2 * time.Minute
+
+// CasaOSClient is a typed client for the CasaOS HTTP API. A single client is
+// created by the provider and shared by every resource and data source, so it
+// is safe for concurrent use.
+type CasaOSClient struct {
+	// endpointMu guards endpoint, which follows the gateway when its port
+	// is changed.
+	endpointMu sync.RWMutex
+	endpoint   *url.URL
+
+	httpClient *http.Client
+
+	username string
+	password string
+
+	// authMu serializes logins and token refreshes, and guards token.
+	authMu sync.Mutex
+	token  authToken
+
+	// singletonsMu guards singletons.
+	singletonsMu sync.Mutex
+	singletons   map[string]bool
+
+	// linksMu serializes changes to the app grid links.
+	linksMu sync.Mutex
+}
+
+// authToken is the token pair handed out by the CasaOS user service.
+type authToken struct {
+	access    string
+	refresh   string
+	expiresAt time.Time
+}
+
+// APIError is returned when CasaOS answers a request with an error status or
+// an unsuccessful response envelope.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("CasaOS API returned HTTP %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("CasaOS API returned HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is an APIError for a missing object.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is an APIError with StatusCode 409, which
+// CasaOS answers when something to create already exists.
+func IsConflict(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// isUnauthorized reports whether err is an APIError for a rejected token.
+func isUnauthorized(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// isTransient reports whether err may go away when the request is retried,
+// because the device answered with a server error or could not be reached.
+func isTransient(err error) bool {
+	var apiErr *APIError
+
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
+
+// apiResponse is the envelope CasaOS wraps around every JSON response. The v1
+// services report their own status code in Success, the v2 services rely on
+// the HTTP status code alone.
+type apiResponse struct {
+	Success int             `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token tokenResponse `json:"token"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+func (t tokenResponse) authToken() authToken {
+	token := authToken{
+		access:  t.AccessToken,
+		refresh: t.RefreshToken,
+	}
+
+	// A missing expiry means the token is only renewed once it is rejected.
+	if t.ExpiresAt > 0 {
+		token.expiresAt = time.Unix(t.ExpiresAt, 0)
+	}
+
+	return token
+}
+
+// NewCasaOSClient returns a client for the CasaOS instance at endpoint. The
+// client is not authenticated until Login is called.
+func NewCasaOSClient(endpoint, username, password string) (*CasaOSClient, error) {
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint %q: scheme must be http or https", endpoint)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+	}
+
+	return &CasaOSClient{
+		endpoint: u,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		username: username,
+		password: password,
+	}, nil
+}
+
+// claimSingleton records that a resource of type typeName, which manages a
+// setting of the whole device, is planned with this client. It reports false
+// when another resource of the same type claimed the device before. The
+// provider configures a new client for every Terraform run, so claims do not
+// outlive a plan or apply. Every provider configuration has a client of its
+// own, so a resource declared through another alias is not detected.
+func (c *CasaOSClient) claimSingleton(typeName string) bool {
+	c.singletonsMu.Lock()
+	defer c.singletonsMu.Unlock()
+
+	if c.singletons[typeName] {
+		return false
+	}
+
+	if c.singletons == nil {
+		c.singletons = map[string]bool{}
+	}
+
+	c.singletons[typeName] = true
+
+	return true
+}
+
+// baseURL returns a copy of the URL of the CasaOS instance.
+func (c *CasaOSClient) baseURL() url.URL {
+	c.endpointMu.RLock()
+	defer c.endpointMu.RUnlock()
+
+	return *c.endpoint
+}
+
+// Endpoint returns the URL of the CasaOS instance.
+func (c *CasaOSClient) Endpoint() string {
+	u := c.baseURL()
+
+	return u.String()
+}
+
+// Hostname returns the host name of the CasaOS endpoint, without port.
+func (c *CasaOSClient) Hostname() string {
+	u := c.baseURL()
+
+	return u.Hostname()
+}
+
+// Login authenticates against the CasaOS user service and stores the tokens
+// used for all subsequent requests.
+func (c *CasaOSClient) Login(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	return c.login(ctx)
+}
+
+// login must be called with authMu held.
+func (c *CasaOSClient) login(ctx context.Context) error {
+	var out loginResponse
+
+	err := c.publicJSON(ctx, http.MethodPost, "/v1/users/login", loginRequest{
+		Username: c.username,
+		Password: c.password,
+	}, &out)
+	if err != nil {
+		return fmt.Errorf("login as %q failed: %w", c.username, err)
+	}
+
+	if out.Token.AccessToken == "" {
+		return fmt.Errorf("login as %q failed: response did not contain an access token", c.username)
+	}
+
+	c.token = out.Token.authToken()
+
+	tflog.Debug(ctx, "logged in to CasaOS", map[string]interface{}{
+		"endpoint":   c.Endpoint(),
+		"username":   c.username,
+		"expires_at": c.token.expiresAt,
+	})
+
+	return nil
+}
+
+// refresh exchanges the refresh token for a new token pair. It must be called
+// with authMu held.
+func (c *CasaOSClient) refresh(ctx context.Context) error {
+	var out tokenResponse
+
+	err := c.publicJSON(ctx, http.MethodPost, "/v1/users/refresh", refreshRequest{
+		RefreshToken: c.token.refresh,
+	}, &out)
+	if err != nil {
+		return err
+	}
+
+	if out.AccessToken == "" {
+		return errors.New("response did not contain an access token")
+	}
+
+	c.token = out.authToken()
+
+	tflog.Debug(ctx, "refreshed CasaOS access token", map[string]interface{}{
+		"expires_at": c.token.expiresAt,
+	})
+
+	return nil
+}
+
+// accessToken returns a token that is valid for at least tokenRefreshWindow,
+// refreshing or logging in again as needed. Concurrent callers wait for a
+// single refresh instead of each hitting the user service.
+func (c *CasaOSClient) accessToken(ctx context.Context) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.token.access == "" {
+		if err := c.login(ctx); err != nil {
+			return "", err
+		}
+
+		return c.token.access, nil
+	}
+
+	if c.token.expiresAt.IsZero() || time.Until(c.token.expiresAt) > tokenRefreshWindow {
+		return c.token.access, nil
+	}
+
+	if c.token.refresh != "" {
+		err := c.refresh(ctx)
+		if err == nil {
+			return c.token.access, nil
+		}
+
+		tflog.Debug(ctx, "refreshing CasaOS access token failed, logging in again", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	if err := c.login(ctx); err != nil {
+		return "", err
+	}
+
+	return c.token.access, nil
+}
+
+// invalidateToken forgets token after the API rejected it, unless another
+// request already replaced it.
+func (c *CasaOSClient) invalidateToken(token string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.token.access == token {
+		c.token = authToken{}
+	}
+}
+
+// doJSON sends in as a JSON body (when non-nil) and decodes the data member of
+// the response envelope into out (when non-nil).
+func (c *CasaOSClient) doJSON(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	var (
+		body        []byte
+		contentType string
+	)
+
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+
+		body = b
+		contentType = "application/json"
+	}
+
+	respBody, err := c.doBody(ctx, method, path, query, contentType, body, "application/json")
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(respBody, out)
+}
+
+// doBody sends an authenticated request and returns the raw response body. A
+// request rejected with HTTP 401 is retried once with a fresh login.
+func (c *CasaOSClient) doBody(ctx context.Context, method, path string, query url.Values, contentType string, body []byte, accept string) ([]byte, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.send(ctx, method, path, query, contentType, body, accept, token)
+	if !isUnauthorized(err) {
+		return respBody, err
+	}
+
+	tflog.Debug(ctx, "CasaOS rejected the access token, logging in again", map[string]interface{}{
+		"method": method,
+		"path":   path,
+	})
+
+	c.invalidateToken(token)
+
+	token, err = c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.send(ctx, method, path, query, contentType, body, accept, token)
+}
+
+// publicJSON sends an unauthenticated JSON request, as used by the login,
+// token refresh and first-boot registration endpoints.
+func (c *CasaOSClient) publicJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	var (
+		body        []byte
+		contentType string
+	)
+
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+
+		body = b
+		contentType = "application/json"
+	}
+
+	respBody, err := c.send(ctx, method, path, nil, contentType, body, "application/json", "")
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(respBody, out)
+}
+
+// send executes a single request and returns the raw response body, converting
+// error statuses into an APIError.
+func (c *CasaOSClient) send(ctx context.Context, method, path string, query url.Values, contentType string, body []byte, accept, token string) ([]byte, error) {
+	u := c.baseURL()
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if token != "" {
+		// CasaOS expects the raw token, without a "Bearer" scheme.
+		req.Header.Set("Authorization", token)
+	}
+
+	tflog.Trace(ctx, "sending CasaOS API request", map[string]interface{}{
+		"method": method,
+		"url":    u.String(),
+	})
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: reading response: %w", method, path, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    errorMessage(b),
+		}
+	}
+
+	// v1 services answer HTTP 200 and put their own status in the envelope,
+	// surface a rejected token the same way as the v2 services do.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		var env apiResponse
+
+		if err := json.Unmarshal(b, &env); err == nil && env.Success == http.StatusUnauthorized {
+			return nil, &APIError{
+				StatusCode: http.StatusUnauthorized,
+				Message:    env.Message,
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// decodeResponse unwraps the CasaOS response envelope in b into out.
+func decodeResponse(b []byte, out interface{}) error {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+
+	var env apiResponse
+
+	if err := json.Unmarshal(b, &env); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	// v1 services answer HTTP 200 and put their own status in "success".
+	if env.Success != 0 && env.Success != http.StatusOK {
+		return &APIError{
+			StatusCode: env.Success,
+			Message:    env.Message,
+		}
+	}
+
+	if out == nil || len(env.Data) == 0 || string(env.Data) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("decoding response data: %w", err)
+	}
+
+	return nil
+}
+
+// errorMessage extracts the message of an error response, falling back to the
+// raw body for responses that are not JSON.
+func errorMessage(b []byte) string {
+	var env apiResponse
+
+	if err := json.Unmarshal(b, &env); err == nil && env.Message != "" {
+		return env.Message
+	}
+
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,301 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// App statuses reported by the CasaOS app management service.
+const (
+	AppStatusRunning  = "running"
+	AppStatusStopped  = "stopped"
+	AppStatusUpdating = "updating"
+)
+
+// Actions accepted by SetComposeAppStatus.
+const (
+	AppActionStart   = "start"
+	AppActionStop    = "stop"
+	AppActionRestart = "restart"
+)
+
+// ComposeApp is an installed app as listed by the app management service.
+type ComposeApp struct {
+	Name            string              `json:"-"`
+	Status          string              `json:"status"`
+	StoreInfo       ComposeAppStoreInfo `json:"store_info"`
+	Compose         ComposeProject      `json:"compose"`
+	UpdateAvailable bool                `json:"update_available"`
+	IsUncontrolled  bool                `json:"is_uncontrolled"`
+}
+
+// ComposeAppStoreInfo is the x-casaos extension of a compose file.
+type ComposeAppStoreInfo struct {
+	StoreAppID    string            `json:"store_app_id"`
+	Main          string            `json:"main"`
+	Title         map[string]string `json:"title"`
+	Tagline       map[string]string `json:"tagline"`
+	Description   map[string]string `json:"description"`
+	Icon          string            `json:"icon"`
+	Author        string            `json:"author"`
+	Developer     string            `json:"developer"`
+	Category      string            `json:"category"`
+	Architectures []string          `json:"architectures"`
+	Hostname      string            `json:"hostname"`
+	Scheme        string            `json:"scheme"`
+	PortMap       string            `json:"port_map"`
+	Index         string            `json:"index"`
+}
+
+// ComposeProject is the JSON form of a compose file.
+type ComposeProject struct {
+	Name     string                    `json:"name"`
+	Services map[string]ComposeService `json:"services"`
+}
+
+// ComposeService is a service of a compose file.
+type ComposeService struct {
+	Image string        `json:"image"`
+	Ports []ComposePort `json:"ports"`
+}
+
+// ComposePort is a published port of a compose service.
+type ComposePort struct {
+	Target    int64  `json:"target"`
+	Published string `json:"published"`
+	Protocol  string `json:"protocol"`
+}
+
+// ComposeAppContainers lists the containers of an installed app, keyed by
+// service name.
+type ComposeAppContainers struct {
+	Main       string                      `json:"main"`
+	Containers map[string]ContainerSummary `json:"containers"`
+}
+
+// ContainerSummary is the Docker summary of a container.
+type ContainerSummary struct {
+	ID    string `json:"Id"`
+	Image string `json:"Image"`
+	State string `json:"State"`
+}
+
+// MainService returns the name of the main service of the app.
+func (a ComposeApp) MainService() string {
+	return mainServiceName(a.StoreInfo, a.Compose)
+}
+
+// mainServiceName returns x-casaos main when set, otherwise the only service
+// of the project.
+func mainServiceName(info ComposeAppStoreInfo, project ComposeProject) string {
+	if info.Main != "" {
+		return info.Main
+	}
+
+	if len(project.Services) == 1 {
+		for name := range project.Services {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// ListComposeApps returns every installed app, keyed by app name.
+func (c *CasaOSClient) ListComposeApps(ctx context.Context) (map[string]ComposeApp, error) {
+	var apps map[string]ComposeApp
+
+	if err := c.doJSON(ctx, http.MethodGet, "/v2/app_management/compose", nil, nil, &apps); err != nil {
+		return nil, err
+	}
+
+	for name, app := range apps {
+		app.Name = name
+		apps[name] = app
+	}
+
+	return apps, nil
+}
+
+// ComposeApp returns an installed app by name, or an APIError with
+// StatusCode 404 when no such app is installed.
+func (c *CasaOSClient) ComposeApp(ctx context.Context, name string) (*ComposeApp, error) {
+	apps, err := c.ListComposeApps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	app, ok := apps[name]
+	if !ok {
+		return nil, &APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no app named %q is installed", name),
+		}
+	}
+
+	return &app, nil
+}
+
+// ComposeAppContainers returns the containers of an installed app.
+func (c *CasaOSClient) ComposeAppContainers(ctx context.Context, name string) (*ComposeAppContainers, error) {
+	var containers ComposeAppContainers
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/app_management/compose/"+url.PathEscape(name)+"/containers", nil, nil, &containers)
+
+	return &containers, err
+}
+
+// InstallComposeApp installs the app described by the compose YAML. CasaOS
+// pulls images and starts the app in the background after accepting it.
+func (c *CasaOSClient) InstallComposeApp(ctx context.Context, compose string) error {
+	_, err := c.doBody(ctx, http.MethodPost, "/v2/app_management/compose", nil, "application/yaml", []byte(compose), "application/json")
+
+	return err
+}
+
+// UpdateComposeApp replaces the compose YAML of an installed app.
+func (c *CasaOSClient) UpdateComposeApp(ctx context.Context, name, compose string) error {
+	_, err := c.doBody(ctx, http.MethodPut, "/v2/app_management/compose/"+url.PathEscape(name), nil, "application/yaml", []byte(compose), "application/json")
+
+	return err
+}
+
+// UninstallComposeApp removes an installed app, and its data under
+// /DATA/AppData when deleteConfigFolder is set.
+func (c *CasaOSClient) UninstallComposeApp(ctx context.Context, name string, deleteConfigFolder bool) error {
+	query := url.Values{
+		"delete_config_folder": []string{strconv.FormatBool(deleteConfigFolder)},
+	}
+
+	return c.doJSON(ctx, http.MethodDelete, "/v2/app_management/compose/"+url.PathEscape(name), query, nil, nil)
+}
+
+// ComposeAppYAML returns the compose YAML CasaOS holds for an installed app.
+func (c *CasaOSClient) ComposeAppYAML(ctx context.Context, name string) (string, error) {
+	b, err := c.doBody(ctx, http.MethodGet, "/v2/app_management/compose/"+url.PathEscape(name), nil, "", nil, "application/yaml")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// ComposeAppStatus returns the status of an installed app, such as
+// AppStatusRunning.
+func (c *CasaOSClient) ComposeAppStatus(ctx context.Context, name string) (string, error) {
+	var status string
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/app_management/compose/"+url.PathEscape(name)+"/status", nil, nil, &status)
+
+	return status, err
+}
+
+// SetComposeAppStatus starts, stops or restarts the containers of an installed
+// app, see the AppAction constants.
+func (c *CasaOSClient) SetComposeAppStatus(ctx context.Context, name, action string) error {
+	return c.doJSON(ctx, http.MethodPut, "/v2/app_management/compose/"+url.PathEscape(name)+"/status", nil, action, nil)
+}
+
+// ComposeAppLogs returns the last lines of the combined container logs of an
+// installed app.
+func (c *CasaOSClient) ComposeAppLogs(ctx context.Context, name string, lines int) (string, error) {
+	var logs string
+
+	query := url.Values{}
+	query.Set("lines", strconv.Itoa(lines))
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/app_management/compose/"+url.PathEscape(name)+"/logs", query, nil, &logs)
+
+	return logs, err
+}
+
+// StoreAppCompose returns the compose YAML of an app in the app store
+// catalog, as it would be installed.
+func (c *CasaOSClient) StoreAppCompose(ctx context.Context, storeAppID string) (string, error) {
+	b, err := c.doBody(ctx, http.MethodGet, "/v2/app_management/apps/"+url.PathEscape(storeAppID)+"/compose", nil, "", nil, "application/yaml")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// AppStore is an app store source registered on the device.
+type AppStore struct {
+	ID       int64  `json:"id"`
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+	StoreDir string `json:"store_root"`
+	AppCount int64  `json:"app_count"`
+}
+
+// ListAppStores returns the app store sources registered on the device.
+func (c *CasaOSClient) ListAppStores(ctx context.Context) ([]AppStore, error) {
+	var stores []AppStore
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/app_management/appstore", nil, nil, &stores)
+
+	return stores, err
+}
+
+// AppStoreByURL returns the app store source registered under storeURL, or an
+// APIError with StatusCode 404 when there is none.
+func (c *CasaOSClient) AppStoreByURL(ctx context.Context, storeURL string) (*AppStore, error) {
+	stores, err := c.ListAppStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, store := range stores {
+		if store.URL == storeURL {
+			return &store, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no app store registered with URL %q", storeURL),
+	}
+}
+
+// RegisterAppStore adds an app store source. CasaOS downloads the catalog from
+// storeURL while registering it.
+func (c *CasaOSClient) RegisterAppStore(ctx context.Context, storeURL string) error {
+	query := url.Values{
+		"url": []string{storeURL},
+	}
+
+	return c.doJSON(ctx, http.MethodPost, "/v2/app_management/appstore", query, nil, nil)
+}
+
+// UnregisterAppStore removes an app store source.
+func (c *CasaOSClient) UnregisterAppStore(ctx context.Context, id int64) error {
+	return c.doJSON(ctx, http.MethodDelete, "/v2/app_management/appstore/"+strconv.FormatInt(id, 10), nil, nil, nil)
+}
+
+// CatalogApp is an app in the catalog of an app store.
+type CatalogApp struct {
+	StoreInfo ComposeAppStoreInfo `json:"store_info"`
+	Compose   ComposeProject      `json:"compose"`
+}
+
+// MainService returns the name of the main service of the catalog app.
+func (a CatalogApp) MainService() string {
+	return mainServiceName(a.StoreInfo, a.Compose)
+}
+
+// ListAppStoreCatalog returns the apps in the catalog of one app store, keyed
+// by store app ID.
+func (c *CasaOSClient) ListAppStoreCatalog(ctx context.Context, storeID int64) (map[string]CatalogApp, error) {
+	var apps map[string]CatalogApp
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/app_management/appstore/"+strconv.FormatInt(storeID, 10)+"/apps", nil, nil, &apps)
+
+	return apps, err
+}
@@ -0,0 +1,249 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// folderPageSize is how many entries are requested per folder listing, large
+// enough for any folder managed through Terraform.
+const folderPageSize = 10000
+
+// FileInfo is a file or folder on the device as listed by the file service.
+type FileInfo struct {
+	Name  string    `json:"name"`
+	Path  string    `json:"path"`
+	Size  int64     `json:"size"`
+	IsDir bool      `json:"is_dir"`
+	Mode  uint32    `json:"mode"`
+	Date  time.Time `json:"date"`
+}
+
+type folderListing struct {
+	Content []FileInfo `json:"content"`
+	Total   int        `json:"total"`
+}
+
+// ListFolder returns the entries of a folder on the device, or an APIError
+// with StatusCode 404 when the folder does not exist.
+func (c *CasaOSClient) ListFolder(ctx context.Context, dir string) ([]FileInfo, error) {
+	query := url.Values{
+		"path":  []string{dir},
+		"index": []string{"1"},
+		"size":  []string{strconv.Itoa(folderPageSize)},
+	}
+
+	var listing folderListing
+
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/folder", query, nil, &listing); err != nil {
+		return nil, err
+	}
+
+	return listing.Content, nil
+}
+
+// StatPath returns a file or folder on the device, or an APIError with
+// StatusCode 404 when there is none. The file service has no stat endpoint,
+// so the parent folder is listed.
+func (c *CasaOSClient) StatPath(ctx context.Context, name string) (*FileInfo, error) {
+	name = path.Clean(name)
+
+	entries, err := c.ListFolder(ctx, path.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Name == path.Base(name) {
+			return &entry, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("%s does not exist", name),
+	}
+}
+
+// CreateFolder creates a folder. Its parent folder must exist.
+func (c *CasaOSClient) CreateFolder(ctx context.Context, dir string) error {
+	in := map[string]string{"path": dir}
+
+	return c.doJSON(ctx, http.MethodPost, "/v1/folder", nil, in, nil)
+}
+
+// RenamePath renames or moves a file or folder.
+func (c *CasaOSClient) RenamePath(ctx context.Context, oldPath, newPath string) error {
+	in := map[string]string{
+		"old_path": oldPath,
+		"new_path": newPath,
+	}
+
+	return c.doJSON(ctx, http.MethodPut, "/v1/folder/name", nil, in, nil)
+}
+
+// DeletePaths deletes files and folders, including everything inside the
+// folders.
+func (c *CasaOSClient) DeletePaths(ctx context.Context, paths ...string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/v1/batch", nil, paths, nil)
+}
+
+// DownloadFile returns the content of a file, or an APIError with StatusCode
+// 404 when there is no such file.
+func (c *CasaOSClient) DownloadFile(ctx context.Context, name string) ([]byte, error) {
+	query := url.Values{
+		"path": []string{name},
+	}
+
+	return c.doBody(ctx, http.MethodGet, "/v1/file", query, "", nil, "application/octet-stream")
+}
+
+// FileUpload describes a chunked upload of a file to the device, in the form
+// resumable.js sends it to the upload endpoint. Chunks are numbered from 1, all
+// but the last one are ChunkSize bytes long.
+type FileUpload struct {
+	// Path is the absolute path of the file on the device.
+	Path string
+
+	// Identifier tells the uploads of different files and contents apart,
+	// so chunks that arrived in an earlier attempt can be reused.
+	Identifier string
+
+	TotalSize int64
+	ChunkSize int64
+}
+
+// NewFileUpload returns the upload of size bytes to name in chunks of
+// chunkSize bytes. The identifier is derived from name, size, chunkSize and
+// checksum, so a retried upload of the same content resumes where the last
+// attempt stopped.
+func NewFileUpload(name string, size, chunkSize int64, checksum string) FileUpload {
+	name = path.Clean(name)
+
+	if len(checksum) > 16 {
+		checksum = checksum[:16]
+	}
+
+	return FileUpload{
+		Path:       name,
+		Identifier: fmt.Sprintf("%d-%d-%s-%s", size, chunkSize, checksum, strings.NewReplacer("/", "", ".", "").Replace(name)),
+		TotalSize:  size,
+		ChunkSize:  chunkSize,
+	}
+}
+
+// TotalChunks returns the number of chunks of the upload. An empty file is
+// sent as a single empty chunk.
+func (u FileUpload) TotalChunks() int {
+	if u.TotalSize == 0 {
+		return 1
+	}
+
+	return int((u.TotalSize + u.ChunkSize - 1) / u.ChunkSize)
+}
+
+// ChunkLength returns the length of chunk number.
+func (u FileUpload) ChunkLength(number int) int64 {
+	if number < u.TotalChunks() {
+		return u.ChunkSize
+	}
+
+	return u.TotalSize - int64(u.TotalChunks()-1)*u.ChunkSize
+}
+
+// fields returns the resumable.js parameters describing chunk number.
+func (u FileUpload) fields(number int) url.Values {
+	return url.Values{
+		"path":             []string{path.Dir(u.Path)},
+		"filename":         []string{path.Base(u.Path)},
+		"relativePath":     []string{path.Base(u.Path)},
+		"identifier":       []string{u.Identifier},
+		"chunkNumber":      []string{strconv.Itoa(number)},
+		"totalChunks":      []string{strconv.Itoa(u.TotalChunks())},
+		"chunkSize":        []string{strconv.FormatInt(u.ChunkSize, 10)},
+		"currentChunkSize": []string{strconv.FormatInt(u.ChunkLength(number), 10)},
+		"totalSize":        []string{strconv.FormatInt(u.TotalSize, 10)},
+	}
+}
+
+// HasUploadChunk reports whether chunk number of the upload reached the
+// device already.
+func (c *CasaOSClient) HasUploadChunk(ctx context.Context, upload FileUpload, number int) (bool, error) {
+	respBody, err := c.doBody(ctx, http.MethodGet, "/v1/file/upload", upload.fields(number), "", nil, "application/json")
+	if err != nil {
+		return false, err
+	}
+
+	// The device answers HTTP 204 without a body for missing chunks.
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return false, nil
+	}
+
+	return true, decodeResponse(respBody, nil)
+}
+
+// UploadChunk sends chunk number of the upload. The device writes the file
+// once it has every chunk, replacing an existing file. The folder of the file
+// must exist.
+func (c *CasaOSClient) UploadChunk(ctx context.Context, upload FileUpload, number int, chunk []byte) error {
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+
+	// Send the fields in a fixed order, the file part last.
+	fields := upload.fields(number)
+
+	for _, name := range []string{"path", "filename", "relativePath", "identifier", "chunkNumber", "totalChunks", "chunkSize", "currentChunkSize", "totalSize"} {
+		if err := form.WriteField(name, fields.Get(name)); err != nil {
+			return fmt.Errorf("building upload: %w", err)
+		}
+	}
+
+	part, err := form.CreateFormFile("file", path.Base(upload.Path))
+	if err != nil {
+		return fmt.Errorf("building upload: %w", err)
+	}
+
+	if _, err := part.Write(chunk); err != nil {
+		return fmt.Errorf("building upload: %w", err)
+	}
+
+	if err := form.Close(); err != nil {
+		return fmt.Errorf("building upload: %w", err)
+	}
+
+	respBody, err := c.doBody(ctx, http.MethodPost, "/v1/file/upload", nil, form.FormDataContentType(), body.Bytes(), "application/json")
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(respBody, nil)
+}
+
+// UploadFile creates or replaces a file with content, sent as a single chunk.
+// The folder of the file must exist.
+func (c *CasaOSClient) UploadFile(ctx context.Context, name string, content []byte) error {
+	upload := NewFileUpload(name, int64(len(content)), int64(len(content)), sha256Hex(content))
+
+	return c.UploadChunk(ctx, upload, 1, content)
+}
+
+// sha256Hex returns the hex encoded SHA-256 checksum of content.
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type gatewayPortRequest struct {
+	Port string `json:"port"`
+}
+
+// GatewayPort returns the port the CasaOS gateway, which serves the web UI
+// and the API, listens on.
+func (c *CasaOSClient) GatewayPort(ctx context.Context) (string, error) {
+	var port string
+
+	err := c.doJSON(ctx, http.MethodGet, "/v1/gateway/port", nil, nil, &port)
+
+	return port, err
+}
+
+// SetGatewayPort moves the CasaOS gateway to port. The gateway answers the
+// request on the old port and restarts on the new one, so the client keeps
+// using the old port until FollowGatewayPort is called.
+func (c *CasaOSClient) SetGatewayPort(ctx context.Context, port string) error {
+	return c.doJSON(ctx, http.MethodPut, "/v1/gateway/port", nil, gatewayPortRequest{Port: port}, nil)
+}
+
+// FollowGatewayPort points the client at port after the gateway moved there
+// from oldPort, and returns the new endpoint. It reports false and leaves the
+// endpoint as it is when the endpoint does not use oldPort, for example
+// because the device is reached through a reverse proxy.
+func (c *CasaOSClient) FollowGatewayPort(oldPort, port string) (string, bool) {
+	c.endpointMu.Lock()
+	defer c.endpointMu.Unlock()
+
+	if endpointPort(c.endpoint.Scheme, c.endpoint.Port()) != oldPort {
+		return c.endpoint.String(), false
+	}
+
+	u := *c.endpoint
+
+	switch host := u.Hostname(); {
+	case endpointPort(u.Scheme, "") != port:
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		// Hostname strips the brackets of an IPv6 address.
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	c.endpoint = &u
+
+	return u.String(), true
+}
+
+// endpointPort returns port, or the default port of scheme when it is empty.
+func endpointPort(scheme, port string) string {
+	if port != "" {
+		return port
+	}
+
+	if scheme == "https" {
+		return "443"
+	}
+
+	return "80"
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// SambaShare is a folder of the device shared over SMB.
+type SambaShare struct {
+	ID        int64  `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Anonymous bool   `json:"anonymous"`
+}
+
+// ListSambaShares returns every SMB share of the device.
+func (c *CasaOSClient) ListSambaShares(ctx context.Context) ([]SambaShare, error) {
+	var shares []SambaShare
+
+	err := c.doJSON(ctx, http.MethodGet, "/v1/samba/shares", nil, nil, &shares)
+
+	return shares, err
+}
+
+// SambaShareByName returns the SMB share called name, or an APIError with
+// StatusCode 404 when there is none.
+func (c *CasaOSClient) SambaShareByName(ctx context.Context, name string) (*SambaShare, error) {
+	shares, err := c.ListSambaShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, share := range shares {
+		if share.Name == name {
+			return &share, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no SMB share named %q", name),
+	}
+}
+
+// CreateSambaShare shares a folder over SMB. The ID of share is ignored.
+func (c *CasaOSClient) CreateSambaShare(ctx context.Context, share SambaShare) error {
+	share.ID = 0
+
+	// The endpoint takes a batch of shares.
+	return c.doJSON(ctx, http.MethodPost, "/v1/samba/shares", nil, []SambaShare{share}, nil)
+}
+
+// DeleteSambaShare stops sharing a folder over SMB.
+func (c *CasaOSClient) DeleteSambaShare(ctx context.Context, id int64) error {
+	return c.doJSON(ctx, http.MethodDelete, "/v1/samba/shares/"+strconv.FormatInt(id, 10), nil, nil, nil)
+}
+
+// SambaConnection is a remote SMB server mounted on the device.
+type SambaConnection struct {
+	ID         int64  `json:"id,omitempty"`
+	Host       string `json:"host"`
+	Port       string `json:"port"`
+	Username   string `json:"username"`
+	Password   string `json:"password,omitempty"`
+	MountPoint string `json:"mount_point,omitempty"`
+}
+
+// ListSambaConnections returns every remote SMB server mounted on the device.
+// Passwords are never returned.
+func (c *CasaOSClient) ListSambaConnections(ctx context.Context) ([]SambaConnection, error) {
+	var connections []SambaConnection
+
+	err := c.doJSON(ctx, http.MethodGet, "/v1/samba/connections", nil, nil, &connections)
+
+	return connections, err
+}
+
+// SambaConnection returns a mounted SMB server by ID, or an APIError with
+// StatusCode 404 when there is none.
+func (c *CasaOSClient) SambaConnection(ctx context.Context, id int64) (*SambaConnection, error) {
+	connections, err := c.ListSambaConnections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, connection := range connections {
+		if connection.ID == id {
+			return &connection, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no SMB connection with ID %d", id),
+	}
+}
+
+// CreateSambaConnection connects to a remote SMB server and mounts its shares
+// on the device. The device connects right away, so an unreachable server or
+// wrong credentials are reported as an APIError.
+func (c *CasaOSClient) CreateSambaConnection(ctx context.Context, connection SambaConnection) (*SambaConnection, error) {
+	var created SambaConnection
+
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/samba/connections", nil, connection, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// DeleteSambaConnection unmounts a remote SMB server.
+func (c *CasaOSClient) DeleteSambaConnection(ctx context.Context, id int64) error {
+	return c.doJSON(ctx, http.MethodDelete, "/v1/samba/connections/"+strconv.FormatInt(id, 10), nil, nil, nil)
+}
@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Disk is a physical disk attached to the device.
+type Disk struct {
+	Path        string      `json:"path"`
+	Model       string      `json:"model"`
+	Serial      string      `json:"serial"`
+	Size        int64       `json:"size"`
+	Type        string      `json:"type"`
+	Health      bool        `json:"health"`
+	SmartStatus string      `json:"smart_status"`
+	Temperature int64       `json:"temperature"`
+	Children    []Partition `json:"children"`
+
+	// State is "formatting" while a storage is being created on the disk,
+	// empty otherwise.
+	State string `json:"state,omitempty"`
+}
+
+// Partition is a partition of a disk.
+type Partition struct {
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	FSType     string `json:"fstype"`
+	MountPoint string `json:"mountpoint"`
+}
+
+// Storage is a partition CasaOS mounted as a storage, with its capacity in
+// bytes.
+type Storage struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	MountPoint string `json:"mount_point"`
+	Disk       string `json:"disk"`
+	Type       string `json:"type"`
+	Size       int64  `json:"size"`
+	Used       int64  `json:"used"`
+	Avail      int64  `json:"avail"`
+}
+
+// ListDisks returns every physical disk attached to the device.
+func (c *CasaOSClient) ListDisks(ctx context.Context) ([]Disk, error) {
+	var disks []Disk
+
+	err := c.doJSON(ctx, http.MethodGet, "/v1/disks", nil, nil, &disks)
+
+	return disks, err
+}
+
+// ListStorages returns every storage of the device.
+func (c *CasaOSClient) ListStorages(ctx context.Context) ([]Storage, error) {
+	var storages []Storage
+
+	err := c.doJSON(ctx, http.MethodGet, "/v1/storage", nil, nil, &storages)
+
+	return storages, err
+}
+
+// DiskByPath returns the disk at devicePath, or an APIError with StatusCode
+// 404 when there is none.
+func (c *CasaOSClient) DiskByPath(ctx context.Context, devicePath string) (*Disk, error) {
+	disks, err := c.ListDisks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, disk := range disks {
+		if disk.Path == devicePath {
+			return &disk, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no disk at %q", devicePath),
+	}
+}
+
+// StorageByMountPoint returns the storage mounted at mountPoint, or an
+// APIError with StatusCode 404 when there is none.
+func (c *CasaOSClient) StorageByMountPoint(ctx context.Context, mountPoint string) (*Storage, error) {
+	storages, err := c.ListStorages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storage := range storages {
+		if storage.MountPoint == mountPoint {
+			return &storage, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no storage mounted at %q", mountPoint),
+	}
+}
+
+// CreateStorage erases the disk at devicePath, formats it with filesystem and
+// mounts it as a storage called name. Formatting continues in the background
+// while the disk is in the "formatting" state.
+func (c *CasaOSClient) CreateStorage(ctx context.Context, devicePath, name, filesystem string) error {
+	in := map[string]interface{}{
+		"path":   devicePath,
+		"name":   name,
+		"fstype": filesystem,
+		"format": true,
+	}
+
+	return c.doJSON(ctx, http.MethodPost, "/v1/storage", nil, in, nil)
+}
+
+// UnmountStorage unmounts the storage at mountPoint. The data stays on the
+// disk.
+func (c *CasaOSClient) UnmountStorage(ctx context.Context, mountPoint string) error {
+	query := url.Values{
+		"mount_point": {mountPoint},
+	}
+
+	return c.doJSON(ctx, http.MethodDelete, "/v1/storage", query, nil, nil)
+}
+
+// MergedStorage is a MergerFS pool of storages mounted as one folder. Its
+// capacity is that of the members still mounted.
+type MergedStorage struct {
+	ID                int64    `json:"id,omitempty"`
+	FSType            string   `json:"fstype,omitempty"`
+	MountPoint        string   `json:"mount_point"`
+	SourceVolumePaths []string `json:"source_volume_paths"`
+	Size              int64    `json:"size,omitempty"`
+	Used              int64    `json:"used,omitempty"`
+	Avail             int64    `json:"avail,omitempty"`
+}
+
+// errMergedStoragesUnsupported is returned by the merged storage methods when
+// the device runs a CasaOS version without MergerFS support.
+var errMergedStoragesUnsupported = errors.New("this CasaOS version does not support merged storages, update CasaOS to pool disks")
+
+// ListMergedStorages returns every merged storage of the device.
+func (c *CasaOSClient) ListMergedStorages(ctx context.Context) ([]MergedStorage, error) {
+	var merges []MergedStorage
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/local_storage/merges", nil, nil, &merges)
+	if IsNotFound(err) {
+		return nil, errMergedStoragesUnsupported
+	}
+
+	return merges, err
+}
+
+// MergedStorage returns the merged storage with the given ID, or an APIError
+// with StatusCode 404 when there is none.
+func (c *CasaOSClient) MergedStorage(ctx context.Context, id int64) (*MergedStorage, error) {
+	merges, err := c.ListMergedStorages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, merge := range merges {
+		if merge.ID == id {
+			return &merge, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no merged storage with ID %d", id),
+	}
+}
+
+// CreateMergedStorage pools the storages mounted at the source volume paths
+// of merge and mounts the pool at its mount point. The ID of merge is
+// ignored.
+func (c *CasaOSClient) CreateMergedStorage(ctx context.Context, merge MergedStorage) (*MergedStorage, error) {
+	var created MergedStorage
+
+	merge.ID = 0
+
+	err := c.doJSON(ctx, http.MethodPost, "/v2/local_storage/merges", nil, merge, &created)
+	if IsNotFound(err) {
+		return nil, errMergedStoragesUnsupported
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateMergedStorage changes the members and mount point of the merged
+// storage with the ID of merge.
+func (c *CasaOSClient) UpdateMergedStorage(ctx context.Context, merge MergedStorage) error {
+	return c.doJSON(ctx, http.MethodPut, "/v2/local_storage/merges/"+strconv.FormatInt(merge.ID, 10), nil, merge, nil)
+}
+
+// DeleteMergedStorage unmounts a merged storage. Its members stay mounted.
+func (c *CasaOSClient) DeleteMergedStorage(ctx context.Context, id int64) error {
+	return c.doJSON(ctx, http.MethodDelete, "/v2/local_storage/merges/"+strconv.FormatInt(id, 10), nil, nil, nil)
+}
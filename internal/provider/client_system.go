@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+)
+
+// SystemVersion is the CasaOS version running on the device.
+type SystemVersion struct {
+	CurrentVersion string `json:"current_version"`
+	NeedUpdate     bool   `json:"need_update"`
+}
+
+// SystemHardware describes the device and its operating system.
+type SystemHardware struct {
+	Arch     string `json:"arch"`
+	Hostname string `json:"hostname"`
+	Kernel   string `json:"kernel"`
+
+	// Uptime is in seconds.
+	Uptime int64 `json:"uptime"`
+}
+
+// SystemUtilization is the current load of the device.
+type SystemUtilization struct {
+	CPU struct {
+		Model   string  `json:"model"`
+		Num     int64   `json:"num"`
+		Percent float64 `json:"percent"`
+	} `json:"cpu"`
+	Mem struct {
+		Total     int64 `json:"total"`
+		Used      int64 `json:"used"`
+		Available int64 `json:"available"`
+	} `json:"mem"`
+	Net []NetworkInterface `json:"net"`
+}
+
+// NetworkInterface is a network interface of the device with its addresses
+// in CIDR notation.
+type NetworkInterface struct {
+	Name string   `json:"name"`
+	IPs  []string `json:"ips"`
+}
+
+// SystemVersion returns the CasaOS version running on the device.
+func (c *CasaOSClient) SystemVersion(ctx context.Context) (*SystemVersion, error) {
+	var version SystemVersion
+
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/version", nil, nil, &version); err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// SystemHardware returns the hardware and operating system facts of the
+// device.
+func (c *CasaOSClient) SystemHardware(ctx context.Context) (*SystemHardware, error) {
+	var hardware SystemHardware
+
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/hardware", nil, nil, &hardware); err != nil {
+		return nil, err
+	}
+
+	return &hardware, nil
+}
+
+// SystemUtilization returns the CPU, memory and network facts of the device.
+func (c *CasaOSClient) SystemUtilization(ctx context.Context) (*SystemUtilization, error) {
+	var utilization SystemUtilization
+
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/utilization", nil, nil, &utilization); err != nil {
+		return nil, err
+	}
+
+	return &utilization, nil
+}
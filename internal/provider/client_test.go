@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCasaOSClient_Login(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/users/login":
+			var in loginRequest
+
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				t.Errorf("decoding login request: %s", err)
+			}
+
+			if in.Username != "casaos" || in.Password != "secret" {
+				_, _ = w.Write([]byte(`{"success":401,"message":"user does not exist or password is invalid"}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"success":200,"message":"ok","data":{"token":{"access_token":"abc","refresh_token":"def","expires_at":4102444800}}}`))
+		case "/v1/users/current":
+			if got := r.Header.Get("Authorization"); got != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"success":200,"message":"ok","data":{"username":"casaos"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewCasaOSClient(srv.URL+"/", "casaos", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var user struct {
+		Username string `json:"username"`
+	}
+
+	if err := client.doJSON(context.Background(), http.MethodGet, "/v1/users/current", nil, nil, &user); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if user.Username != "casaos" {
+		t.Errorf("expected username casaos, got %q", user.Username)
+	}
+
+	bad, err := NewCasaOSClient(srv.URL, "casaos", "wrong")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := bad.Login(context.Background()); err == nil {
+		t.Error("expected login with a wrong password to fail")
+	}
+}
+
+// tokenServer is a minimal CasaOS user service handing out numbered tokens
+// that expire after lifetime.
+type tokenServer struct {
+	lifetime time.Duration
+
+	logins    atomic.Int32
+	refreshes atomic.Int32
+	issued    atomic.Int32
+
+	mu      sync.Mutex
+	revoked map[string]bool
+}
+
+func (s *tokenServer) token() string {
+	n := s.issued.Add(1)
+
+	return fmt.Sprintf(`{"access_token":"access-%[1]d","refresh_token":"refresh-%[1]d","expires_at":%[2]d}`, n, time.Now().Add(s.lifetime).Unix())
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1/users/login":
+		s.logins.Add(1)
+		_, _ = fmt.Fprintf(w, `{"success":200,"message":"ok","data":{"token":%s}}`, s.token())
+	case "/v1/users/refresh":
+		s.refreshes.Add(1)
+		_, _ = fmt.Fprintf(w, `{"success":200,"message":"ok","data":%s}`, s.token())
+	default:
+		s.mu.Lock()
+		revoked := s.revoked[r.Header.Get("Authorization")]
+		s.mu.Unlock()
+
+		if r.Header.Get("Authorization") == "" || revoked {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"success":200,"message":"ok"}`))
+	}
+}
+
+func TestCasaOSClient_RefreshBeforeExpiry(t *testing.T) {
+	// Tokens expire inside the refresh window, so only the first token
+	// handed out by login should be refreshed.
+	ts := &tokenServer{lifetime: time.Minute}
+	srv := httptest.NewServer(ts)
+	defer srv.Close()
+
+	client, err := NewCasaOSClient(srv.URL, "casaos", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ts.lifetime = time.Hour
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := client.doJSON(context.Background(), http.MethodGet, "/v1/users/current", nil, nil, nil); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := ts.logins.Load(); got != 1 {
+		t.Errorf("expected 1 login, got %d", got)
+	}
+
+	if got := ts.refreshes.Load(); got != 1 {
+		t.Errorf("expected 1 refresh, got %d", got)
+	}
+}
+
+func TestCasaOSClient_ReloginOnUnauthorized(t *testing.T) {
+	ts := &tokenServer{lifetime: time.Hour, revoked: map[string]bool{}}
+	srv := httptest.NewServer(ts)
+	defer srv.Close()
+
+	client, err := NewCasaOSClient(srv.URL, "casaos", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Revoke the token behind the client's back, e.g. after a device reboot.
+	ts.mu.Lock()
+	ts.revoked["access-1"] = true
+	ts.mu.Unlock()
+
+	if err := client.doJSON(context.Background(), http.MethodGet, "/v1/users/current", nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := ts.logins.Load(); got != 2 {
+		t.Errorf("expected 2 logins, got %d", got)
+	}
+
+	if got := ts.refreshes.Load(); got != 0 {
+		t.Errorf("expected no refresh, got %d", got)
+	}
+}
+
+func TestNewCasaOSClient_InvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"casaos.local", "ftp://casaos.local", "http://"} {
+		if _, err := NewCasaOSClient(endpoint, "casaos", "secret"); err == nil {
+			t.Errorf("expected endpoint %q to be rejected", endpoint)
+		}
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !IsNotFound(&APIError{StatusCode: http.StatusNotFound}) {
+		t.Error("expected HTTP 404 to be reported as not found")
+	}
+
+	if IsNotFound(&APIError{StatusCode: http.StatusInternalServerError}) {
+		t.Error("expected HTTP 500 not to be reported as not found")
+	}
+}
+
+func TestCasaOSClient_FollowGatewayPort(t *testing.T) {
+	for _, tc := range []struct {
+		endpoint, oldPort, port string
+		expected                string
+		followed                bool
+	}{
+		{"http://casaos.local", "80", "8080", "http://casaos.local:8080", true},
+		{"http://casaos.local:8080/", "8080", "80", "http://casaos.local", true},
+		{"https://casaos.local", "443", "8443", "https://casaos.local:8443", true},
+		{"http://[fd00::1]:8080", "8080", "80", "http://[fd00::1]", true},
+		{"https://casaos.example.com", "80", "8080", "https://casaos.example.com", false},
+	} {
+		client, err := NewCasaOSClient(tc.endpoint, "casaos", "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		endpoint, followed := client.FollowGatewayPort(tc.oldPort, tc.port)
+		if endpoint != tc.expected || followed != tc.followed {
+			t.Errorf("moving %s from port %s to %s: expected %s (%t), got %s (%t)",
+				tc.endpoint, tc.oldPort, tc.port, tc.expected, tc.followed, endpoint, followed)
+		}
+
+		if client.Endpoint() != endpoint {
+			t.Errorf("expected the client to use %s, got %s", endpoint, client.Endpoint())
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// UserStatus tells whether the owner account of the device exists yet.
+type UserStatus struct {
+	Initialized bool `json:"initialized"`
+
+	// Key must accompany the registration of the owner.
+	Key string `json:"key"`
+}
+
+// User is a CasaOS account.
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Avatar   string `json:"avatar"`
+}
+
+// UserUpdate changes the account the client is logged in as. Nil fields are
+// left unchanged.
+type UserUpdate struct {
+	Username *string `json:"username,omitempty"`
+	Avatar   *string `json:"avatar,omitempty"`
+}
+
+// UserStatus reports whether the owner of the device has been registered. It
+// needs no login, a freshly installed device has no account to log in with.
+func (c *CasaOSClient) UserStatus(ctx context.Context) (*UserStatus, error) {
+	var status UserStatus
+
+	if err := c.publicJSON(ctx, http.MethodGet, "/v1/users/status", nil, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// RegisterOwner registers the owner account of a freshly installed device.
+// The client logs in with the new account from then on.
+func (c *CasaOSClient) RegisterOwner(ctx context.Context, username, password string) error {
+	status, err := c.UserStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	in := map[string]string{
+		"username": username,
+		"password": password,
+		"key":      status.Key,
+	}
+
+	if err := c.publicJSON(ctx, http.MethodPost, "/v1/users/register", in, nil); err != nil {
+		return err
+	}
+
+	c.SetCredentials(username, password)
+
+	return nil
+}
+
+// CurrentUser returns the account the client is logged in as.
+func (c *CasaOSClient) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/users/current", nil, nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// UpdateCurrentUser changes the account the client is logged in as. A new
+// username is used for later logins.
+func (c *CasaOSClient) UpdateCurrentUser(ctx context.Context, update UserUpdate) (*User, error) {
+	var user User
+
+	if err := c.doJSON(ctx, http.MethodPut, "/v1/users/current", nil, update, &user); err != nil {
+		return nil, err
+	}
+
+	if update.Username != nil {
+		c.authMu.Lock()
+		c.username = *update.Username
+		c.authMu.Unlock()
+	}
+
+	return &user, nil
+}
+
+// ChangePassword changes the password of the account the client is logged in
+// as, and uses it for later logins.
+func (c *CasaOSClient) ChangePassword(ctx context.Context, password string) error {
+	c.authMu.Lock()
+	oldPassword := c.password
+	c.authMu.Unlock()
+
+	in := map[string]string{
+		"old_password": oldPassword,
+		"password":     password,
+	}
+
+	if err := c.doJSON(ctx, http.MethodPut, "/v1/users/current/password", nil, in, nil); err != nil {
+		return err
+	}
+
+	c.authMu.Lock()
+	c.password = password
+	c.authMu.Unlock()
+
+	return nil
+}
+
+// Credentials returns the username and password the client logs in with.
+func (c *CasaOSClient) Credentials() (string, string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	return c.username, c.password
+}
+
+// SetCredentials makes the client log in with username and password from
+// now on. The current token stays in use until it is rejected.
+func (c *CasaOSClient) SetCredentials(username, password string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.username = username
+	c.password = password
+}
+
+// WithCredentials returns a client for the same device that logs in with
+// username and password instead.
+func (c *CasaOSClient) WithCredentials(username, password string) *CasaOSClient {
+	u := c.baseURL()
+
+	return &CasaOSClient{
+		endpoint:   &u,
+		httpClient: c.httpClient,
+		username:   username,
+		password:   password,
+	}
+}
+
+// CustomData returns the JSON document stored under key in the custom data
+// of the current user, or an APIError with StatusCode 404 when there is none.
+// The dashboard keeps its preferences there.
+func (c *CasaOSClient) CustomData(ctx context.Context, key string) (json.RawMessage, error) {
+	var value json.RawMessage
+
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/users/current/custom/"+url.PathEscape(key), nil, nil, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// SetCustomData stores value, encoded as JSON, under key in the custom data
+// of the current user.
+func (c *CasaOSClient) SetCustomData(ctx context.Context, key string, value interface{}) error {
+	return c.doJSON(ctx, http.MethodPut, "/v1/users/current/custom/"+url.PathEscape(key), nil, value, nil)
+}
+
+// DeleteCustomData removes key from the custom data of the current user.
+func (c *CasaOSClient) DeleteCustomData(ctx context.Context, key string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/v1/users/current/custom/"+url.PathEscape(key), nil, nil, nil)
+}
@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Keys of the custom data of the current user that the dashboard keeps its
+// app grid in.
+const (
+	appOrderKey  = "app_order"
+	appHiddenKey = "app_hidden"
+	appLinksKey  = "app_links"
+)
+
+// widgetSettingsKey is the key of the custom data of the current user that
+// the dashboard keeps its widget settings in.
+const widgetSettingsKey = "widget_settings"
+
+// AppGridTile is an app or external link shown on the dashboard app grid.
+type AppGridTile struct {
+	// Name is the app name, or the ID of a link.
+	Name   string `json:"name"`
+	Title  string `json:"title"`
+	Type   string `json:"type"`
+	Hidden bool   `json:"hidden"`
+}
+
+// AppGrid returns the tiles of the dashboard app grid in the order they are
+// shown.
+func (c *CasaOSClient) AppGrid(ctx context.Context) ([]AppGridTile, error) {
+	var tiles []AppGridTile
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/casaos/web/appgrid", nil, nil, &tiles)
+
+	return tiles, err
+}
+
+// customDataList decodes the JSON array stored under key in the custom data
+// of the current user into list, leaving it empty when nothing is stored.
+func (c *CasaOSClient) customDataList(ctx context.Context, key string, list interface{}) error {
+	value, err := c.CustomData(ctx, key)
+	if IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(value, list); err != nil {
+		return fmt.Errorf("custom data %q is not a list the dashboard understands: %w", key, err)
+	}
+
+	return nil
+}
+
+// AppOrder returns the names of the app grid tiles in the order the user
+// arranged them. Tiles the user never moved may be missing.
+func (c *CasaOSClient) AppOrder(ctx context.Context) ([]string, error) {
+	order := []string{}
+
+	err := c.customDataList(ctx, appOrderKey, &order)
+
+	return order, err
+}
+
+// SetAppOrder arranges the app grid tiles in the order of names.
+func (c *CasaOSClient) SetAppOrder(ctx context.Context, names []string) error {
+	return c.SetCustomData(ctx, appOrderKey, names)
+}
+
+// HiddenApps returns the names of the app grid tiles hidden from the
+// dashboard.
+func (c *CasaOSClient) HiddenApps(ctx context.Context) ([]string, error) {
+	hidden := []string{}
+
+	err := c.customDataList(ctx, appHiddenKey, &hidden)
+
+	return hidden, err
+}
+
+// SetHiddenApps hides the app grid tiles in names and shows every other one.
+func (c *CasaOSClient) SetHiddenApps(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		err := c.DeleteCustomData(ctx, appHiddenKey)
+		if IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	return c.SetCustomData(ctx, appHiddenKey, names)
+}
+
+// AppLink is an external link shown as a tile on the dashboard app grid.
+type AppLink struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Icon   string `json:"icon"`
+	NewTab bool   `json:"new_tab"`
+}
+
+// ListAppLinks returns the external links on the app grid of the current
+// user.
+func (c *CasaOSClient) ListAppLinks(ctx context.Context) ([]AppLink, error) {
+	links := []AppLink{}
+
+	err := c.customDataList(ctx, appLinksKey, &links)
+
+	return links, err
+}
+
+// AppLink returns the external link with the given ID, or an APIError with
+// StatusCode 404 when there is none.
+func (c *CasaOSClient) AppLink(ctx context.Context, id string) (*AppLink, error) {
+	links, err := c.ListAppLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, link := range links {
+		if link.ID == id {
+			return &link, nil
+		}
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no app grid link with ID %q", id),
+	}
+}
+
+// CreateAppLink adds link to the app grid with a new ID and returns it. The
+// ID of link is ignored. IDs are random, so a link never gets the ID of a
+// deleted one that casaos_app_grid may still refer to.
+func (c *CasaOSClient) CreateAppLink(ctx context.Context, link AppLink) (*AppLink, error) {
+	err := c.updateAppLinks(ctx, func(links []AppLink) ([]AppLink, error) {
+		used := map[string]bool{}
+
+		for _, l := range links {
+			used[l.ID] = true
+		}
+
+		link.ID = ""
+
+		for link.ID == "" || used[link.ID] {
+			id, err := newAppLinkID()
+			if err != nil {
+				return nil, err
+			}
+
+			link.ID = id
+		}
+
+		return append(links, link), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// newAppLinkID returns a random app grid link ID such as "link-3f2a9c1e5b7d".
+func newAppLinkID() (string, error) {
+	b := make([]byte, 6)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating link ID: %w", err)
+	}
+
+	return "link-" + hex.EncodeToString(b), nil
+}
+
+// UpdateAppLink replaces the external link with the ID of link.
+func (c *CasaOSClient) UpdateAppLink(ctx context.Context, link AppLink) error {
+	return c.updateAppLinks(ctx, func(links []AppLink) ([]AppLink, error) {
+		for i := range links {
+			if links[i].ID == link.ID {
+				links[i] = link
+				return links, nil
+			}
+		}
+
+		return nil, &APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no app grid link with ID %q", link.ID),
+		}
+	})
+}
+
+// DeleteAppLink removes the external link with the given ID from the app
+// grid.
+func (c *CasaOSClient) DeleteAppLink(ctx context.Context, id string) error {
+	return c.updateAppLinks(ctx, func(links []AppLink) ([]AppLink, error) {
+		for i := range links {
+			if links[i].ID == id {
+				return append(links[:i], links[i+1:]...), nil
+			}
+		}
+
+		return nil, &APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no app grid link with ID %q", id),
+		}
+	})
+}
+
+// updateAppLinks stores the links change returns for the current links. The
+// links are kept in a single document, so changes are serialized to keep
+// resources applied in parallel from overwriting each other.
+func (c *CasaOSClient) updateAppLinks(ctx context.Context, change func([]AppLink) ([]AppLink, error)) error {
+	c.linksMu.Lock()
+	defer c.linksMu.Unlock()
+
+	links, err := c.ListAppLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	links, err = change(links)
+	if err != nil {
+		return err
+	}
+
+	return c.SetCustomData(ctx, appLinksKey, links)
+}
+
+// DashboardWidget is a widget the dashboard of the device can show.
+type DashboardWidget struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ListDashboardWidgets returns the widgets the device has, in the order the
+// dashboard shows them.
+func (c *CasaOSClient) ListDashboardWidgets(ctx context.Context) ([]DashboardWidget, error) {
+	var widgets []DashboardWidget
+
+	err := c.doJSON(ctx, http.MethodGet, "/v2/casaos/web/widgets", nil, nil, &widgets)
+
+	return widgets, err
+}
+
+// WidgetSetting is whether the dashboard shows a widget. The dashboard shows
+// the widgets in the order of their settings.
+type WidgetSetting struct {
+	Name string `json:"name"`
+	Show bool   `json:"show"`
+}
+
+// WidgetSettings returns the widget settings of the current user. The list
+// is empty when the user never changed them.
+func (c *CasaOSClient) WidgetSettings(ctx context.Context) ([]WidgetSetting, error) {
+	settings := []WidgetSetting{}
+
+	err := c.customDataList(ctx, widgetSettingsKey, &settings)
+
+	return settings, err
+}
+
+// SetWidgetSettings stores the widget settings of the current user. Without
+// settings the dashboard shows every widget in its default order.
+func (c *CasaOSClient) SetWidgetSettings(ctx context.Context, settings []WidgetSetting) error {
+	if len(settings) == 0 {
+		err := c.DeleteCustomData(ctx, widgetSettingsKey)
+		if IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	return c.SetCustomData(ctx, widgetSettingsKey, settings)
+}
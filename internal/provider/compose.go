@@ -0,0 +1,285 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeHeader holds the parts of a CasaOS compose file the provider needs
+// before handing the file to the device.
+type composeHeader struct {
+	Name    string                 `yaml:"name"`
+	XCasaOS map[string]interface{} `yaml:"x-casaos"`
+}
+
+// parseComposeHeader decodes the project name and x-casaos extension of a
+// compose file. CasaOS uses the project name as the app identifier.
+func parseComposeHeader(compose string) (composeHeader, error) {
+	var header composeHeader
+
+	if err := yaml.Unmarshal([]byte(compose), &header); err != nil {
+		return header, fmt.Errorf("compose is not valid YAML: %w", err)
+	}
+
+	if header.Name == "" {
+		return header, errors.New("compose must set the top-level project name, CasaOS uses it as the app name")
+	}
+
+	if header.XCasaOS == nil {
+		return header, errors.New("compose must contain a top-level x-casaos extension describing the app")
+	}
+
+	return header, nil
+}
+
+// composeOverrides are user supplied changes applied to the main service of a
+// catalog compose file before it is installed.
+type composeOverrides struct {
+	// Ports maps container ports to published host ports.
+	Ports map[string]string
+	// Volumes maps container paths to host paths.
+	Volumes map[string]string
+	// Environment sets environment variables.
+	Environment map[string]string
+	// WebUIPort replaces the x-casaos port_map the dashboard links to.
+	WebUIPort string
+}
+
+// composeMainService returns the name of the service CasaOS treats as the main
+// one: x-casaos main when set, otherwise the only service.
+func composeMainService(project map[string]interface{}) (string, error) {
+	services, _ := project["services"].(map[string]interface{})
+
+	if xcasaos, ok := project["x-casaos"].(map[string]interface{}); ok {
+		if main, ok := xcasaos["main"].(string); ok && main != "" {
+			if _, ok := services[main]; !ok {
+				return "", fmt.Errorf("x-casaos main service %q is not defined in services", main)
+			}
+
+			return main, nil
+		}
+	}
+
+	switch len(services) {
+	case 0:
+		return "", errors.New("compose does not define any services")
+	case 1:
+		for name := range services {
+			return name, nil
+		}
+	}
+
+	return "", errors.New("compose defines several services but no x-casaos main service")
+}
+
+// composeAppVersion returns the image tag of the main service, which CasaOS
+// app stores use as the app version.
+func composeAppVersion(compose string) (string, error) {
+	var project map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(compose), &project); err != nil {
+		return "", fmt.Errorf("compose is not valid YAML: %w", err)
+	}
+
+	main, err := composeMainService(project)
+	if err != nil {
+		return "", err
+	}
+
+	service, _ := project["services"].(map[string]interface{})[main].(map[string]interface{})
+	image, _ := service["image"].(string)
+
+	return imageTag(image), nil
+}
+
+// imageTag returns the tag of a Docker image reference, "latest" when it has
+// none.
+func imageTag(image string) string {
+	// Strip a digest first, then take the tag after the last path segment so
+	// registry ports are not mistaken for tags.
+	image, _, _ = strings.Cut(image, "@")
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+
+	return "latest"
+}
+
+// applyComposeOverrides returns compose with overrides applied to its main
+// service. Ports and volumes are matched on their container side, in both
+// the short and the long compose syntax.
+func applyComposeOverrides(compose string, overrides composeOverrides) (string, error) {
+	var project map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(compose), &project); err != nil {
+		return "", fmt.Errorf("compose is not valid YAML: %w", err)
+	}
+
+	main, err := composeMainService(project)
+	if err != nil {
+		return "", err
+	}
+
+	service, ok := project["services"].(map[string]interface{})[main].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("service %q is not a mapping", main)
+	}
+
+	if err := overridePorts(service, overrides.Ports); err != nil {
+		return "", err
+	}
+
+	overrideVolumes(service, overrides.Volumes)
+	overrideEnvironment(service, overrides.Environment)
+
+	if overrides.WebUIPort != "" {
+		xcasaos, ok := project["x-casaos"].(map[string]interface{})
+		if !ok {
+			xcasaos = map[string]interface{}{}
+			project["x-casaos"] = xcasaos
+		}
+
+		xcasaos["port_map"] = overrides.WebUIPort
+	}
+
+	b, err := yaml.Marshal(project)
+	if err != nil {
+		return "", fmt.Errorf("encoding compose: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func overridePorts(service map[string]interface{}, ports map[string]string) error {
+	if len(ports) == 0 {
+		return nil
+	}
+
+	list, _ := service["ports"].([]interface{})
+	seen := map[string]bool{}
+
+	for i, entry := range list {
+		switch port := entry.(type) {
+		case map[string]interface{}:
+			target := fmt.Sprint(port["target"])
+
+			if published, ok := ports[target]; ok {
+				port["published"] = published
+				seen[target] = true
+			}
+		case string, int:
+			// Short syntax: [host_ip:][published:]target[/protocol]
+			spec := fmt.Sprint(port)
+			spec, protocol, _ := strings.Cut(spec, "/")
+			parts := strings.Split(spec, ":")
+			target := parts[len(parts)-1]
+
+			published, ok := ports[target]
+			if !ok {
+				continue
+			}
+
+			replaced := published + ":" + target
+			if len(parts) == 3 {
+				replaced = parts[0] + ":" + replaced
+			}
+
+			if protocol != "" {
+				replaced += "/" + protocol
+			}
+
+			list[i] = replaced
+			seen[target] = true
+		}
+	}
+
+	for target, published := range ports {
+		if !seen[target] {
+			return fmt.Errorf("main service does not expose container port %s", target)
+		}
+
+		if published == "" {
+			return fmt.Errorf("published port for container port %s must not be empty", target)
+		}
+	}
+
+	return nil
+}
+
+func overrideVolumes(service map[string]interface{}, volumes map[string]string) {
+	if len(volumes) == 0 {
+		return
+	}
+
+	list, _ := service["volumes"].([]interface{})
+	seen := map[string]bool{}
+
+	for i, entry := range list {
+		switch volume := entry.(type) {
+		case map[string]interface{}:
+			target := fmt.Sprint(volume["target"])
+
+			if source, ok := volumes[target]; ok {
+				volume["type"] = "bind"
+				volume["source"] = source
+				seen[target] = true
+			}
+		case string:
+			// Short syntax: source:target[:mode]
+			parts := strings.Split(volume, ":")
+			if len(parts) < 2 {
+				continue
+			}
+
+			if source, ok := volumes[parts[1]]; ok {
+				parts[0] = source
+				list[i] = strings.Join(parts, ":")
+				seen[parts[1]] = true
+			}
+		}
+	}
+
+	// Volumes the catalog app does not declare are added as extra binds.
+	for target, source := range volumes {
+		if !seen[target] {
+			list = append(list, map[string]interface{}{
+				"type":   "bind",
+				"source": source,
+				"target": target,
+			})
+		}
+	}
+
+	service["volumes"] = list
+}
+
+func overrideEnvironment(service map[string]interface{}, environment map[string]string) {
+	if len(environment) == 0 {
+		return
+	}
+
+	env := map[string]interface{}{}
+
+	switch existing := service["environment"].(type) {
+	case map[string]interface{}:
+		env = existing
+	case []interface{}:
+		for _, entry := range existing {
+			name, value, _ := strings.Cut(fmt.Sprint(entry), "=")
+			env[name] = value
+		}
+	}
+
+	for name, value := range environment {
+		env[name] = value
+	}
+
+	service["environment"] = env
+}
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testComposeJellyfin = `
+name: jellyfin
+services:
+  jellyfin:
+    image: linuxserver/jellyfin:10.8.13
+    environment:
+      - PUID=1000
+      - PGID=1000
+    ports:
+      - 8097:8096
+      - target: 8920
+        published: "8920"
+        protocol: tcp
+    volumes:
+      - /DATA/AppData/jellyfin/config:/config
+      - type: bind
+        source: /DATA/Media
+        target: /media
+x-casaos:
+  main: jellyfin
+  port_map: "8097"
+`
+
+func TestParseComposeHeader(t *testing.T) {
+	header, err := parseComposeHeader(testComposeJellyfin)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if header.Name != "jellyfin" {
+		t.Errorf("expected name jellyfin, got %q", header.Name)
+	}
+
+	for _, compose := range []string{
+		"services: [",
+		"services: {}\nx-casaos: {}\n",
+		"name: test\nservices: {}\n",
+	} {
+		if _, err := parseComposeHeader(compose); err == nil {
+			t.Errorf("expected compose %q to be rejected", compose)
+		}
+	}
+}
+
+func TestComposeAppVersion(t *testing.T) {
+	for image, expected := range map[string]string{
+		"linuxserver/jellyfin:10.8.13":               "10.8.13",
+		"linuxserver/jellyfin":                       "latest",
+		"registry.local:5000/jellyfin":               "latest",
+		"registry.local:5000/jellyfin:1.2":           "1.2",
+		"linuxserver/jellyfin:10.8.13@sha256:abcdef": "10.8.13",
+	} {
+		version, err := composeAppVersion("services:\n  app:\n    image: " + image + "\n")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if version != expected {
+			t.Errorf("image %s: expected version %q, got %q", image, expected, version)
+		}
+	}
+}
+
+func TestComposeMainService(t *testing.T) {
+	for compose, expected := range map[string]string{
+		"services: {}\n":                                 "compose does not define any services",
+		"services:\n  app: {}\n  db: {}\n":               "compose defines several services but no x-casaos main service",
+		"services:\n  app: {}\nx-casaos:\n  main: web\n": `x-casaos main service "web" is not defined in services`,
+	} {
+		var project map[string]interface{}
+
+		if err := yaml.Unmarshal([]byte(compose), &project); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if _, err := composeMainService(project); err == nil || err.Error() != expected {
+			t.Errorf("compose %q: expected error %q, got %v", compose, expected, err)
+		}
+	}
+}
+
+func TestApplyComposeOverrides(t *testing.T) {
+	compose, err := applyComposeOverrides(testComposeJellyfin, composeOverrides{
+		Ports:       map[string]string{"8096": "18096", "8920": "18920"},
+		Volumes:     map[string]string{"/config": "/DATA/AppData/jf", "/cache": "/DATA/Cache"},
+		Environment: map[string]string{"PUID": "1001", "TZ": "Europe/Berlin"},
+		WebUIPort:   "18096",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var project struct {
+		Services map[string]struct {
+			Environment map[string]string `yaml:"environment"`
+			Ports       []interface{}     `yaml:"ports"`
+			Volumes     []interface{}     `yaml:"volumes"`
+		} `yaml:"services"`
+		XCasaOS struct {
+			PortMap string `yaml:"port_map"`
+		} `yaml:"x-casaos"`
+	}
+
+	if err := yaml.Unmarshal([]byte(compose), &project); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	service := project.Services["jellyfin"]
+
+	if got := service.Ports[0]; got != "18096:8096" {
+		t.Errorf("expected short port to be rewritten, got %v", got)
+	}
+
+	if got := service.Ports[1].(map[string]interface{})["published"]; got != "18920" {
+		t.Errorf("expected long port to be rewritten, got %v", got)
+	}
+
+	if got := service.Volumes[0]; got != "/DATA/AppData/jf:/config" {
+		t.Errorf("expected short volume to be rewritten, got %v", got)
+	}
+
+	if got := len(service.Volumes); got != 3 {
+		t.Errorf("expected an extra volume to be added, got %d volumes", got)
+	}
+
+	if service.Environment["PUID"] != "1001" || service.Environment["PGID"] != "1000" || service.Environment["TZ"] != "Europe/Berlin" {
+		t.Errorf("unexpected environment %v", service.Environment)
+	}
+
+	if project.XCasaOS.PortMap != "18096" {
+		t.Errorf("expected port_map 18096, got %q", project.XCasaOS.PortMap)
+	}
+
+	_, err = applyComposeOverrides(testComposeJellyfin, composeOverrides{
+		Ports: map[string]string{"1234": "1234"},
+	})
+	if err == nil {
+		t.Error("expected overriding an unknown container port to fail")
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &DashboardWidgetsResource{}
+var _ resource.ResourceWithImportState = &DashboardWidgetsResource{}
+var _ resource.ResourceWithModifyPlan = &DashboardWidgetsResource{}
+
+func NewDashboardWidgetsResource() resource.Resource {
+	return &DashboardWidgetsResource{}
+}
+
+// DashboardWidgetsResource defines the resource implementation.
+type DashboardWidgetsResource struct {
+	client *CasaOSClient
+}
+
+// DashboardWidgetsResourceModel describes the resource data model.
+type DashboardWidgetsResourceModel struct {
+	Enabled types.List   `tfsdk:"enabled"`
+	Id      types.String `tfsdk:"id"`
+}
+
+// dashboardWidgetsID is the ID of the only widget settings of a device.
+const dashboardWidgetsID = "dashboard_widgets"
+
+func (r *DashboardWidgetsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dashboard_widgets"
+}
+
+func (r *DashboardWidgetsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Chooses the widgets shown on the dashboard of the logged in user, such as the clock, the system status and the storage usage, and their order.\n\n" +
+			"A device has a single set of widget settings, so declare this resource at most once per device, also across provider aliases. The provider only detects a second one declared with the same provider configuration. " +
+			"Destroying the resource shows every widget again in its default order.",
+
+		Attributes: map[string]schema.Attribute{
+			"enabled": schema.ListAttribute{
+				MarkdownDescription: "Names of the widgets to show, in this order, for example `clock` or `system_status`. Every other widget is hidden. " +
+					"Names the device does not report, for example widgets of a newer CasaOS version, are stored with a warning and take effect once the device has them.",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Widget settings identifier, always `dashboard_widgets`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *DashboardWidgetsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan refuses a second set of widget settings for the same device and
+// warns about widgets the device does not report.
+func (r *DashboardWidgetsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	if !r.client.claimSingleton("casaos_dashboard_widgets") {
+		resp.Diagnostics.AddError(
+			"Duplicate Dashboard Widgets",
+			fmt.Sprintf("More than one casaos_dashboard_widgets resource is declared for the CasaOS device at %s. "+
+				"A device has a single set of widget settings, declare the resource only once.", r.client.Endpoint()),
+		)
+
+		return
+	}
+
+	var plan DashboardWidgetsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.Enabled.IsUnknown() {
+		return
+	}
+
+	widgets, err := r.client.ListDashboardWidgets(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list the dashboard widgets, got error: %s", err))
+		return
+	}
+
+	known := map[string]bool{}
+
+	for _, widget := range widgets {
+		known[widget.Name] = true
+	}
+
+	var unknown []string
+
+	for _, element := range plan.Enabled.Elements() {
+		// Names that are not known until apply are not checked.
+		name, ok := element.(types.String)
+		if !ok || name.IsUnknown() || known[name.ValueString()] {
+			continue
+		}
+
+		unknown = append(unknown, name.ValueString())
+	}
+
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(widgets))
+
+		for _, widget := range widgets {
+			names = append(names, widget.Name)
+		}
+
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("enabled"),
+			"Unknown Dashboard Widgets",
+			fmt.Sprintf("The device at %s has no widget named %s, it has %s. "+
+				"The widgets are stored anyway and shown once the device has them, for example after an update of CasaOS.",
+				r.client.Endpoint(), strings.Join(unknown, ", "), strings.Join(names, ", ")),
+		)
+	}
+}
+
+func (r *DashboardWidgetsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DashboardWidgetsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(dashboardWidgetsID)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DashboardWidgetsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DashboardWidgetsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, err := r.client.WidgetSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the widget settings, got error: %s", err))
+		return
+	}
+
+	enabled := []string{}
+
+	for _, setting := range settings {
+		if setting.Show {
+			enabled = append(enabled, setting.Name)
+		}
+	}
+
+	// Without settings the dashboard shows the widgets of the device.
+	if len(settings) == 0 {
+		widgets, err := r.client.ListDashboardWidgets(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list the dashboard widgets, got error: %s", err))
+			return
+		}
+
+		for _, widget := range widgets {
+			if widget.Enabled {
+				enabled = append(enabled, widget.Name)
+			}
+		}
+	}
+
+	value, diags := types.ListValueFrom(ctx, types.StringType, enabled)
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Enabled = value
+	data.Id = types.StringValue(dashboardWidgetsID)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DashboardWidgetsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data DashboardWidgetsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DashboardWidgetsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data DashboardWidgetsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.SetWidgetSettings(ctx, nil); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reset the widget settings, got error: %s", err))
+		return
+	}
+}
+
+func (r *DashboardWidgetsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != dashboardWidgetsID {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("The widget settings are imported with the ID %q, got %q.", dashboardWidgetsID, req.ID))
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// apply stores widget settings that show the widgets data enables, in their
+// order, and hide every other widget of the device.
+func (r *DashboardWidgetsResource) apply(ctx context.Context, data *DashboardWidgetsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var enabled []string
+
+	diags.Append(data.Enabled.ElementsAs(ctx, &enabled, false)...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	widgets, err := r.client.ListDashboardWidgets(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list the dashboard widgets, got error: %s", err))
+		return diags
+	}
+
+	settings := make([]WidgetSetting, 0, len(enabled)+len(widgets))
+	listed := map[string]bool{}
+
+	for _, name := range enabled {
+		settings = append(settings, WidgetSetting{Name: name, Show: true})
+		listed[name] = true
+	}
+
+	for _, widget := range widgets {
+		if !listed[widget.Name] {
+			settings = append(settings, WidgetSetting{Name: widget.Name, Show: false})
+		}
+	}
+
+	if err := r.client.SetWidgetSettings(ctx, settings); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to store the widget settings, got error: %s", err))
+		return diags
+	}
+
+	tflog.Trace(ctx, "stored the widget settings", map[string]interface{}{
+		"enabled": enabled,
+	})
+
+	return diags
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccDashboardWidgetsResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccDashboardWidgetsResourceConfig(`["system_status", "clock"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "enabled.#", "2"),
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "enabled.0", "system_status"),
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "enabled.1", "clock"),
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "id", "dashboard_widgets"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_dashboard_widgets.test",
+				ImportState:       true,
+				ImportStateId:     "dashboard_widgets",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccDashboardWidgetsResourceConfig(`["clock", "storage", "network"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "enabled.#", "3"),
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "enabled.0", "clock"),
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "enabled.2", "network"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccDashboardWidgetsResource_UnknownWidget(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A widget of a newer CasaOS version is stored with a warning
+			{
+				Config: testAccDashboardWidgetsResourceConfig(`["tf_acc_future", "clock"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_dashboard_widgets.test", "enabled.0", "tf_acc_future"),
+					func(*terraform.State) error {
+						expected := `[{"name":"tf_acc_future","show":true},{"name":"clock","show":true},` +
+							`{"name":"system_status","show":false},{"name":"storage","show":false},` +
+							`{"name":"network","show":false},{"name":"app_store_hints","show":false}]`
+
+						if settings := mock.CustomData("widget_settings"); settings != expected {
+							return fmt.Errorf("expected the other widgets to be hidden, got %s", settings)
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboardWidgetsResource_Duplicate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardWidgetsResourceConfig(`["clock"]`) + `
+resource "casaos_dashboard_widgets" "other" {
+  enabled = ["storage"]
+}
+`,
+				ExpectError: regexp.MustCompile(`More than one casaos_dashboard_widgets resource is\s+declared`),
+			},
+		},
+	})
+}
+
+func TestAccDashboardWidgetsResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardWidgetsResourceConfig(`["clock", "storage"]`),
+			},
+			// A widget shown on the dashboard is hidden again
+			{
+				PreConfig: func() {
+					mock.SetCustomData("widget_settings", `[{"name":"clock","show":true},{"name":"storage","show":true},{"name":"network","show":true}]`)
+				},
+				Config:             testAccDashboardWidgetsResourceConfig(`["clock", "storage"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and reset settings are stored again
+			{
+				PreConfig: func() {
+					mock.SetCustomData("widget_settings", `[]`)
+				},
+				Config:             testAccDashboardWidgetsResourceConfig(`["clock", "storage"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccDashboardWidgetsResourceConfig(enabled string) string {
+	return fmt.Sprintf(`
+resource "casaos_dashboard_widgets" "test" {
+  enabled = %[1]s
+}
+`, enabled)
+}
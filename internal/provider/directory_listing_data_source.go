@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	pathpkg "path"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DirectoryListingDataSource{}
+
+func NewDirectoryListingDataSource() datasource.DataSource {
+	return &DirectoryListingDataSource{}
+}
+
+// DirectoryListingDataSource defines the data source implementation.
+type DirectoryListingDataSource struct {
+	client *CasaOSClient
+}
+
+// DirectoryListingDataSourceModel describes the data source data model.
+type DirectoryListingDataSourceModel struct {
+	Entries  []directoryEntryModel `tfsdk:"entries"`
+	MaxDepth types.Int64           `tfsdk:"max_depth"`
+	Path     types.String          `tfsdk:"path"`
+	Pattern  types.String          `tfsdk:"pattern"`
+}
+
+// directoryEntryModel describes a file or folder in a directory listing.
+type directoryEntryModel struct {
+	IsDir    types.Bool   `tfsdk:"is_dir"`
+	Mode     types.String `tfsdk:"mode"`
+	Modified types.String `tfsdk:"modified"`
+	Name     types.String `tfsdk:"name"`
+	Path     types.String `tfsdk:"path"`
+	Size     types.Int64  `tfsdk:"size"`
+}
+
+func (d *DirectoryListingDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_directory_listing"
+}
+
+func (d *DirectoryListingDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists the files and folders in a folder on the device, optionally including subfolders.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the folder to list, for example `/DATA/Media`.",
+				Required:            true,
+				Validators: []validator.String{
+					absolutePathValidator,
+				},
+			},
+			"pattern": schema.StringAttribute{
+				MarkdownDescription: "Only list entries whose name matches this glob pattern, for example `*.mkv`. " +
+					"The syntax is that of Go's [`path.Match`](https://pkg.go.dev/path#Match). Subfolders are searched whether their own name matches or not.",
+				Optional: true,
+			},
+			"max_depth": schema.Int64Attribute{
+				MarkdownDescription: "How many levels of folders to list. `1`, the default, lists only the entries of `path`, `2` also lists the entries of its subfolders, and so on.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 32),
+				},
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching entries, sorted by path.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the entry.",
+							Computed:            true,
+						},
+						"path": schema.StringAttribute{
+							MarkdownDescription: "Absolute path of the entry.",
+							Computed:            true,
+						},
+						"size": schema.Int64Attribute{
+							MarkdownDescription: "Size of the entry in bytes.",
+							Computed:            true,
+						},
+						"mode": schema.StringAttribute{
+							MarkdownDescription: "Permission bits of the entry in octal, for example `0755`.",
+							Computed:            true,
+						},
+						"modified": schema.StringAttribute{
+							MarkdownDescription: "Time the entry was last modified, in RFC 3339 format.",
+							Computed:            true,
+						},
+						"is_dir": schema.BoolAttribute{
+							MarkdownDescription: "Whether the entry is a folder.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DirectoryListingDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *DirectoryListingDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DirectoryListingDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pattern := data.Pattern.ValueString()
+
+	if _, err := pathpkg.Match(pattern, ""); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pattern"), "Invalid Pattern", fmt.Sprintf("Pattern %q is not a valid glob pattern: %s", pattern, err))
+		return
+	}
+
+	maxDepth := 1
+
+	if !data.MaxDepth.IsNull() {
+		maxDepth = int(data.MaxDepth.ValueInt64())
+	}
+
+	data.Entries = []directoryEntryModel{}
+
+	// Walk the folders breadth first, one listing per folder.
+	dirs := []string{pathpkg.Clean(data.Path.ValueString())}
+
+	for depth := 1; depth <= maxDepth && len(dirs) > 0; depth++ {
+		var next []string
+
+		for _, dir := range dirs {
+			entries, err := d.client.ListFolder(ctx, dir)
+
+			switch {
+			case IsNotFound(err) && depth == 1:
+				resp.Diagnostics.AddAttributeError(path.Root("path"), "Folder Not Found", fmt.Sprintf("Folder %q does not exist on the device.", dir))
+				return
+			case IsNotFound(err):
+				// Deleted while listing its parent.
+				continue
+			case err != nil:
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list folder %q, got error: %s", dir, err))
+				return
+			}
+
+			for _, entry := range entries {
+				if entry.IsDir {
+					next = append(next, pathpkg.Join(dir, entry.Name))
+				}
+
+				if matched, _ := pathpkg.Match(pattern, entry.Name); pattern == "" || matched {
+					data.Entries = append(data.Entries, newDirectoryEntryModel(dir, entry))
+				}
+			}
+		}
+
+		dirs = next
+	}
+
+	sort.Slice(data.Entries, func(i, j int) bool {
+		return data.Entries[i].Path.ValueString() < data.Entries[j].Path.ValueString()
+	})
+
+	tflog.Trace(ctx, "read directory listing", map[string]interface{}{
+		"path":    data.Path.ValueString(),
+		"entries": len(data.Entries),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func newDirectoryEntryModel(dir string, entry FileInfo) directoryEntryModel {
+	return directoryEntryModel{
+		IsDir:    types.BoolValue(entry.IsDir),
+		Mode:     types.StringValue(fmt.Sprintf("%04o", os.FileMode(entry.Mode).Perm())),
+		Modified: types.StringValue(entry.Date.UTC().Format(time.RFC3339)),
+		Name:     types.StringValue(entry.Name),
+		Path:     types.StringValue(pathpkg.Join(dir, entry.Name)),
+		Size:     types.Int64Value(entry.Size),
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDirectoryListingDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccDirectoryListingDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.top", "entries.#", "2"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.top", "entries.0.name", "movie.mkv"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.top", "entries.0.path", "/DATA/Documents/tf-acc-listing/movie.mkv"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.top", "entries.0.size", "5"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.top", "entries.0.is_dir", "false"),
+					resource.TestCheckResourceAttrSet("data.casaos_directory_listing.top", "entries.0.mode"),
+					resource.TestCheckResourceAttrSet("data.casaos_directory_listing.top", "entries.0.modified"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.top", "entries.1.name", "series"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.top", "entries.1.is_dir", "true"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.movies", "entries.#", "2"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.movies", "entries.0.path", "/DATA/Documents/tf-acc-listing/movie.mkv"),
+					resource.TestCheckResourceAttr("data.casaos_directory_listing.movies", "entries.1.path", "/DATA/Documents/tf-acc-listing/series/episode.mkv"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDirectoryListingDataSource_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "casaos_directory_listing" "test" {
+  path = "/DATA/tf-acc-does-not-exist"
+}
+`,
+				ExpectError: regexp.MustCompile(`Folder Not Found`),
+			},
+			{
+				Config: `
+data "casaos_directory_listing" "test" {
+  path    = "/DATA"
+  pattern = "[a-"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Pattern`),
+			},
+		},
+	})
+}
+
+const testAccDirectoryListingDataSourceConfig = `
+resource "casaos_folder" "listing" {
+  path = "/DATA/Documents/tf-acc-listing"
+}
+
+resource "casaos_folder" "series" {
+  path = "${casaos_folder.listing.path}/series"
+}
+
+resource "casaos_file" "movie" {
+  path    = "${casaos_folder.listing.path}/movie.mkv"
+  content = "movie"
+}
+
+resource "casaos_file" "episode" {
+  path    = "${casaos_folder.series.path}/episode.mkv"
+  content = "episode"
+}
+
+resource "casaos_file" "notes" {
+  path    = "${casaos_folder.series.path}/notes.txt"
+  content = "notes"
+}
+
+data "casaos_directory_listing" "top" {
+  path = casaos_folder.listing.path
+
+  depends_on = [casaos_file.movie, casaos_file.episode, casaos_file.notes]
+}
+
+data "casaos_directory_listing" "movies" {
+  path      = casaos_folder.listing.path
+  pattern   = "*.mkv"
+  max_depth = 2
+
+  depends_on = [casaos_file.movie, casaos_file.episode, casaos_file.notes]
+}
+`
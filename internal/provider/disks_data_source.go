@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DisksDataSource{}
+
+func NewDisksDataSource() datasource.DataSource {
+	return &DisksDataSource{}
+}
+
+// DisksDataSource defines the data source implementation.
+type DisksDataSource struct {
+	client *CasaOSClient
+}
+
+// DisksDataSourceModel describes the data source data model.
+type DisksDataSourceModel struct {
+	Disks []diskModel `tfsdk:"disks"`
+}
+
+// diskModel describes a physical disk attached to the device.
+type diskModel struct {
+	Healthy     types.Bool       `tfsdk:"healthy"`
+	Model       types.String     `tfsdk:"model"`
+	Partitions  []partitionModel `tfsdk:"partitions"`
+	Path        types.String     `tfsdk:"path"`
+	Serial      types.String     `tfsdk:"serial"`
+	Size        types.Int64      `tfsdk:"size"`
+	SmartStatus types.String     `tfsdk:"smart_status"`
+	Temperature types.Int64      `tfsdk:"temperature"`
+	Type        types.String     `tfsdk:"type"`
+}
+
+// partitionModel describes a partition of a disk.
+type partitionModel struct {
+	Filesystem types.String `tfsdk:"filesystem"`
+	MountPoint types.String `tfsdk:"mount_point"`
+	Path       types.String `tfsdk:"path"`
+	Size       types.Int64  `tfsdk:"size"`
+}
+
+func (d *DisksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_disks"
+}
+
+func (d *DisksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists the physical disks attached to the device with their health and partitions, for example to check that a data disk is present before creating a storage on it.",
+
+		Attributes: map[string]schema.Attribute{
+			"disks": schema.ListNestedAttribute{
+				MarkdownDescription: "Disks, sorted by path.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							MarkdownDescription: "Device path of the disk, for example `/dev/sdb`.",
+							Computed:            true,
+						},
+						"model": schema.StringAttribute{
+							MarkdownDescription: "Model name reported by the disk.",
+							Computed:            true,
+						},
+						"serial": schema.StringAttribute{
+							MarkdownDescription: "Serial number of the disk.",
+							Computed:            true,
+						},
+						"size": schema.Int64Attribute{
+							MarkdownDescription: "Capacity of the disk in bytes.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Kind of disk, for example `HDD`, `SSD` or `USB`.",
+							Computed:            true,
+						},
+						"healthy": schema.BoolAttribute{
+							MarkdownDescription: "Whether CasaOS considers the disk healthy.",
+							Computed:            true,
+						},
+						"smart_status": schema.StringAttribute{
+							MarkdownDescription: "Result of the SMART self-assessment, `PASSED` or `FAILED`. Empty when the disk does not support SMART.",
+							Computed:            true,
+						},
+						"temperature": schema.Int64Attribute{
+							MarkdownDescription: "Temperature of the disk in degrees Celsius, `0` when the disk does not report it.",
+							Computed:            true,
+						},
+						"partitions": schema.ListNestedAttribute{
+							MarkdownDescription: "Partitions of the disk, empty for a blank disk.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"path": schema.StringAttribute{
+										MarkdownDescription: "Device path of the partition, for example `/dev/sdb1`.",
+										Computed:            true,
+									},
+									"size": schema.Int64Attribute{
+										MarkdownDescription: "Size of the partition in bytes.",
+										Computed:            true,
+									},
+									"filesystem": schema.StringAttribute{
+										MarkdownDescription: "Filesystem of the partition, for example `ext4`.",
+										Computed:            true,
+									},
+									"mount_point": schema.StringAttribute{
+										MarkdownDescription: "Where the partition is mounted, empty when it is not.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DisksDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *DisksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DisksDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	disks, err := d.client.ListDisks(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list disks, got error: %s", err))
+		return
+	}
+
+	sort.Slice(disks, func(i, j int) bool {
+		return disks[i].Path < disks[j].Path
+	})
+
+	data.Disks = make([]diskModel, 0, len(disks))
+
+	for _, disk := range disks {
+		model := diskModel{
+			Healthy:     types.BoolValue(disk.Health),
+			Model:       types.StringValue(disk.Model),
+			Partitions:  make([]partitionModel, 0, len(disk.Children)),
+			Path:        types.StringValue(disk.Path),
+			Serial:      types.StringValue(disk.Serial),
+			Size:        types.Int64Value(disk.Size),
+			SmartStatus: types.StringValue(disk.SmartStatus),
+			Temperature: types.Int64Value(disk.Temperature),
+			Type:        types.StringValue(disk.Type),
+		}
+
+		for _, partition := range disk.Children {
+			model.Partitions = append(model.Partitions, partitionModel{
+				Filesystem: types.StringValue(partition.FSType),
+				MountPoint: types.StringValue(partition.MountPoint),
+				Path:       types.StringValue(partition.Path),
+				Size:       types.Int64Value(partition.Size),
+			})
+		}
+
+		data.Disks = append(data.Disks, model)
+	}
+
+	tflog.Trace(ctx, "read disks", map[string]interface{}{
+		"count": len(data.Disks),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDisksDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccDisksDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("data.casaos_disks.test", "disks.0.path", regexp.MustCompile(`^/dev/`)),
+					resource.TestCheckResourceAttrSet("data.casaos_disks.test", "disks.0.model"),
+					resource.TestCheckResourceAttrSet("data.casaos_disks.test", "disks.0.serial"),
+					resource.TestMatchResourceAttr("data.casaos_disks.test", "disks.0.size", regexp.MustCompile(`^[1-9]\d*$`)),
+					resource.TestCheckResourceAttrSet("data.casaos_disks.test", "disks.0.healthy"),
+					resource.TestCheckResourceAttrSet("data.casaos_disks.test", "disks.0.temperature"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDisksDataSource_Mock(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccDisksDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.#", "3"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.path", "/dev/sdb"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.model", "WDC WD40EFAX-68JH4N1"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.serial", "WD-WX12D3456789"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.size", "4294967296000"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.type", "HDD"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.healthy", "true"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.smart_status", "PASSED"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.temperature", "38"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.partitions.0.path", "/dev/sdb1"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.partitions.0.filesystem", "ext4"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.1.partitions.0.mount_point", "/media/Storage1"),
+					resource.TestCheckResourceAttr("data.casaos_disks.test", "disks.2.partitions.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDisksDataSourceConfig = `
+data "casaos_disks" "test" {}
+`
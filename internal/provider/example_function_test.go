@@ -22,7 +22,7 @@ func TestExampleFunction_Known(t *testing.T) {
 			{
 				Config: `
 				output "test" {
-					value = provider::scaffolding::example("testvalue")
+					value = provider::casaos::example("testvalue")
 				}
 				`,
 				Check: resource.ComposeAggregateTestCheckFunc(
@@ -43,7 +43,7 @@ func TestExampleFunction_Null(t *testing.T) {
 			{
 				Config: `
 				output "test" {
-					value = provider::scaffolding::example(null)
+					value = provider::casaos::example(null)
 				}
 				`,
 				// The parameter does not enable AllowNullValue
@@ -67,7 +67,7 @@ func TestExampleFunction_Unknown(t *testing.T) {
 				}
 				
 				output "test" {
-					value = provider::scaffolding::example(terraform_data.test.output)
+					value = provider::casaos::example(terraform_data.test.output)
 				}
 				`,
 				Check: resource.ComposeAggregateTestCheckFunc(
@@ -0,0 +1,310 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FileResource{}
+var _ resource.ResourceWithImportState = &FileResource{}
+var _ resource.ResourceWithModifyPlan = &FileResource{}
+
+func NewFileResource() resource.Resource {
+	return &FileResource{}
+}
+
+// FileResource defines the resource implementation.
+type FileResource struct {
+	client *CasaOSClient
+}
+
+// FileResourceModel describes the resource data model.
+type FileResourceModel struct {
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	Id            types.String `tfsdk:"id"`
+	Path          types.String `tfsdk:"path"`
+	Sha256        types.String `tfsdk:"sha256"`
+	Source        types.String `tfsdk:"source"`
+}
+
+func (r *FileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_file"
+}
+
+func (r *FileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	contentSources := []path.Expression{
+		path.MatchRoot("content"),
+		path.MatchRoot("content_base64"),
+		path.MatchRoot("source"),
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages a file on the device through the CasaOS file service, such as a configuration file of an app. " +
+			"The whole file is uploaded and downloaded at once, so this is meant for small files. " +
+			"Exactly one of `content`, `content_base64` and `source` must be set.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the file, for example `/DATA/AppData/nginx/nginx.conf`. The folder of the file must exist. An existing file is replaced. Changing this replaces the file.",
+				Required:            true,
+				Validators: []validator.String{
+					absolutePathValidator,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "Content of the file as UTF-8 text.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(contentSources...),
+				},
+			},
+			"content_base64": schema.StringAttribute{
+				MarkdownDescription: "Content of the file encoded as base64, for binary files.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(contentSources...),
+				},
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Path of a local file, on the machine running Terraform, to upload. Changes to the local file are detected through `sha256`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(contentSources...),
+				},
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "Hex encoded SHA-256 checksum of the file content. When the file is edited on the device, it no longer matches the configured content and the next apply uploads the file again.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "File identifier, same as `path`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *FileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan plans the checksum of the configured content. Read stores the
+// checksum of the file on the device, so the plan shows a change whenever
+// either side differs from the last upload.
+func (r *FileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data FileResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Content.IsUnknown() || data.ContentBase64.IsUnknown() || data.Source.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), types.StringUnknown())...)
+		return
+	}
+
+	content, diags := data.content()
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), types.StringValue(sha256Hex(content)))...)
+}
+
+func (r *FileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.upload(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.Path
+
+	tflog.Trace(ctx, "uploaded a file", map[string]interface{}{
+		"path":   data.Path.ValueString(),
+		"sha256": data.Sha256.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, err := r.client.DownloadFile(ctx, data.Path.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "file no longer exists, removing from state", map[string]interface{}{
+			"path": data.Path.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read file %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+
+	// The configured content stays as it is, a file edited on the device
+	// shows up as a different checksum.
+	data.Id = data.Path
+	data.Sha256 = types.StringValue(sha256Hex(content))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.upload(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeletePaths(ctx, data.Path.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete file %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+}
+
+func (r *FileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("path"), req, resp)
+}
+
+// upload writes the configured content to the device and records its
+// checksum in the model.
+func (r *FileResource) upload(ctx context.Context, data *FileResourceModel) diag.Diagnostics {
+	content, diags := data.content()
+	if diags.HasError() {
+		return diags
+	}
+
+	err := r.client.UploadFile(ctx, data.Path.ValueString(), content)
+	if err != nil {
+		addFolderError(&diags, "upload", data.Path.ValueString(), err)
+		return diags
+	}
+
+	data.Sha256 = types.StringValue(sha256Hex(content))
+
+	return diags
+}
+
+// content returns the configured file content, whichever attribute it is set
+// with.
+func (m *FileResourceModel) content() ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	switch {
+	case !m.ContentBase64.IsNull():
+		content, err := base64.StdEncoding.DecodeString(m.ContentBase64.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("content_base64"), "Invalid Base64 Content", fmt.Sprintf("Unable to decode content_base64: %s", err))
+		}
+
+		return content, diags
+	case !m.Source.IsNull():
+		content, err := os.ReadFile(m.Source.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("source"), "Unable to Read Source File", fmt.Sprintf("Unable to read %q: %s", m.Source.ValueString(), err))
+		}
+
+		return content, diags
+	default:
+		return []byte(m.Content.ValueString()), diags
+	}
+}
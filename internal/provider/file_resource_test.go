@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccFileResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccFileResourceConfig("content", "listen 80;\n"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_file.test", "id", "/DATA/Documents/tf-acc.conf"),
+					resource.TestCheckResourceAttr("casaos_file.test", "path", "/DATA/Documents/tf-acc.conf"),
+					resource.TestCheckResourceAttr("casaos_file.test", "sha256", sha256Hex([]byte("listen 80;\n"))),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "casaos_file.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"content"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccFileResourceConfig("content_base64", "AAEC/w=="),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_file.test", "sha256", sha256Hex([]byte{0x00, 0x01, 0x02, 0xff})),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccFileResource_Source(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "nginx.conf")
+
+	if err := os.WriteFile(source, []byte("listen 80;\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFileResourceConfig("source", source),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_file.test", "sha256", sha256Hex([]byte("listen 80;\n"))),
+				),
+			},
+			// Editing the local file uploads it again
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(source, []byte("listen 8080;\n"), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccFileResourceConfig("source", source),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_file.test", "sha256", sha256Hex([]byte("listen 8080;\n"))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFileResource_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "casaos_file" "test" {
+  path           = "/DATA/Documents/tf-acc.conf"
+  content        = "a"
+  content_base64 = "YQ=="
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config:      testAccFileResourceConfig("content_base64", "not base64"),
+				ExpectError: regexp.MustCompile(`Invalid Base64 Content`),
+			},
+			{
+				Config: `
+resource "casaos_file" "test" {
+  path    = "/DATA/tf-acc-missing/tf-acc.conf"
+  content = "a"
+}
+`,
+				ExpectError: regexp.MustCompile(`Parent Folder Not Found`),
+			},
+		},
+	})
+}
+
+func TestAccFileResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFileResourceConfig("content", "listen 80;\n"),
+			},
+			// A file edited on the device is detected
+			{
+				PreConfig: func() {
+					mock.WriteFile("/DATA/Documents/tf-acc.conf", []byte("listen 81;\n"))
+				},
+				Config:             testAccFileResourceConfig("content", "listen 80;\n"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and uploaded again
+			{
+				Config: testAccFileResourceConfig("content", "listen 80;\n"),
+				Check: func(*terraform.State) error {
+					if content, _ := mock.ReadFile("/DATA/Documents/tf-acc.conf"); string(content) != "listen 80;\n" {
+						return fmt.Errorf("expected the file to be restored, got %q", content)
+					}
+
+					return nil
+				},
+			},
+			// A file deleted on the device is created again
+			{
+				PreConfig: func() {
+					mock.Remove("/DATA/Documents/tf-acc.conf")
+				},
+				Config:             testAccFileResourceConfig("content", "listen 80;\n"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccFileResourceConfig(attribute string, value string) string {
+	return fmt.Sprintf(`
+resource "casaos_file" "test" {
+  path = "/DATA/Documents/tf-acc.conf"
+  %[1]s = %[2]q
+}
+`, attribute, value)
+}
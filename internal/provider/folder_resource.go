@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FolderResource{}
+var _ resource.ResourceWithImportState = &FolderResource{}
+
+func NewFolderResource() resource.Resource {
+	return &FolderResource{}
+}
+
+// FolderResource defines the resource implementation.
+type FolderResource struct {
+	client *CasaOSClient
+}
+
+// FolderResourceModel describes the resource data model.
+type FolderResourceModel struct {
+	ForceDestroy types.Bool   `tfsdk:"force_destroy"`
+	Id           types.String `tfsdk:"id"`
+	Path         types.String `tfsdk:"path"`
+}
+
+// absolutePathValidator checks that a path on the device is absolute.
+var absolutePathValidator = stringvalidator.RegexMatches(regexp.MustCompile(`^/.`), "must be an absolute path below /")
+
+func (r *FolderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_folder"
+}
+
+func (r *FolderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages a folder on the device through the CasaOS file service.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the folder, for example `/DATA/AppData/nginx`. The parent folder must exist. Changing this renames the folder in place, keeping its content.",
+				Required:            true,
+				Validators: []validator.String{
+					absolutePathValidator,
+				},
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether destroying the resource deletes the folder together with everything inside it. When `false`, destroying a folder that is not empty fails. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Folder identifier, same as `path`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *FolderResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *FolderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FolderResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.CreateFolder(ctx, data.Path.ValueString())
+	if err != nil {
+		addFolderError(&resp.Diagnostics, "create", data.Path.ValueString(), err)
+		return
+	}
+
+	data.Id = data.Path
+
+	tflog.Trace(ctx, "created a folder", map[string]interface{}{
+		"path": data.Path.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FolderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FolderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	info, err := r.client.StatPath(ctx, data.Path.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "folder no longer exists, removing from state", map[string]interface{}{
+			"path": data.Path.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read folder %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+
+	if !info.IsDir {
+		resp.Diagnostics.AddError(
+			"Not a Folder",
+			fmt.Sprintf("%q is a file on the device, not a folder. Remove the file or the resource.", data.Path.ValueString()),
+		)
+
+		return
+	}
+
+	data.Id = data.Path
+
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FolderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state FolderResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Path.Equal(state.Path) {
+		err := r.client.RenamePath(ctx, state.Path.ValueString(), data.Path.ValueString())
+		if err != nil {
+			addFolderError(&resp.Diagnostics, "rename", data.Path.ValueString(), err)
+			return
+		}
+
+		tflog.Trace(ctx, "renamed a folder", map[string]interface{}{
+			"old_path": state.Path.ValueString(),
+			"new_path": data.Path.ValueString(),
+		})
+	}
+
+	data.Id = data.Path
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FolderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FolderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ForceDestroy.ValueBool() {
+		entries, err := r.client.ListFolder(ctx, data.Path.ValueString())
+		if IsNotFound(err) {
+			return
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read folder %q, got error: %s", data.Path.ValueString(), err))
+			return
+		}
+
+		if len(entries) > 0 {
+			resp.Diagnostics.AddError(
+				"Folder Not Empty",
+				fmt.Sprintf("Folder %q contains %d entries and force_destroy is not set. "+
+					"Empty the folder, or set force_destroy = true and apply before destroying to delete its content as well.",
+					data.Path.ValueString(), len(entries)),
+			)
+
+			return
+		}
+	}
+
+	err := r.client.DeletePaths(ctx, data.Path.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete folder %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+}
+
+func (r *FolderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("path"), req, resp)
+}
+
+// addFolderError adds the diagnostic for a failed attempt to create a file or
+// folder at name, pointing out the usual causes.
+func addFolderError(diags *diag.Diagnostics, action string, name string, err error) {
+	switch {
+	case IsNotFound(err):
+		diags.AddAttributeError(
+			path.Root("path"),
+			"Parent Folder Not Found",
+			fmt.Sprintf("Unable to %s %q because its parent folder does not exist on the device: %s", action, name, err),
+		)
+	case IsConflict(err):
+		diags.AddAttributeError(
+			path.Root("path"),
+			"Path Already Exists",
+			fmt.Sprintf("Unable to %s %q because something already exists at that path. Import it or choose another path.", action, name),
+		)
+	default:
+		diags.AddError("Client Error", fmt.Sprintf("Unable to %s %q, got error: %s", action, name, err))
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccFolderResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccFolderResourceConfig("/DATA/Documents/tf-acc-folder"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_folder.test", "id", "/DATA/Documents/tf-acc-folder"),
+					resource.TestCheckResourceAttr("casaos_folder.test", "path", "/DATA/Documents/tf-acc-folder"),
+					resource.TestCheckResourceAttr("casaos_folder.test", "force_destroy", "false"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_folder.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccFolderResourceConfig("/DATA/Documents/tf-acc-renamed"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_folder.test", "id", "/DATA/Documents/tf-acc-renamed"),
+					resource.TestCheckResourceAttr("casaos_folder.test", "path", "/DATA/Documents/tf-acc-renamed"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccFolderResource_MissingParent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccFolderResourceConfig("/DATA/tf-acc-missing/child"),
+				ExpectError: regexp.MustCompile(`Parent Folder Not Found`),
+			},
+		},
+	})
+}
+
+func TestAccFolderResource_Rename(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFolderResourceConfig("/DATA/Documents/tf-acc-folder"),
+				Check: func(*terraform.State) error {
+					mock.WriteFile("/DATA/Documents/tf-acc-folder/keep.txt", []byte("keep"))
+					return nil
+				},
+			},
+			// Renaming keeps the content of the folder
+			{
+				Config: testAccFolderResourceConfig("/DATA/Documents/tf-acc-renamed"),
+				Check: func(*terraform.State) error {
+					if _, ok := mock.ReadFile("/DATA/Documents/tf-acc-renamed/keep.txt"); !ok {
+						return fmt.Errorf("expected keep.txt to move with its folder")
+					}
+
+					return nil
+				},
+			},
+			// A folder that is not empty is only deleted with force_destroy
+			{
+				Config:      testAccFolderResourceConfig("/DATA/Documents/tf-acc-renamed"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Folder Not Empty`),
+			},
+			{
+				Config: testAccFolderResourceForceDestroyConfig("/DATA/Documents/tf-acc-renamed"),
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if mock.Exists("/DATA/Documents/tf-acc-renamed") {
+				return fmt.Errorf("expected the folder to be deleted with its content")
+			}
+
+			return nil
+		},
+	})
+}
+
+func TestAccFolderResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFolderResourceConfig("/DATA/Documents/tf-acc-folder"),
+			},
+			// A folder deleted on the device is created again
+			{
+				PreConfig: func() {
+					mock.Remove("/DATA/Documents/tf-acc-folder")
+				},
+				Config:             testAccFolderResourceConfig("/DATA/Documents/tf-acc-folder"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccFolderResourceConfig(path string) string {
+	return fmt.Sprintf(`
+resource "casaos_folder" "test" {
+  path = %[1]q
+}
+`, path)
+}
+
+func testAccFolderResourceForceDestroyConfig(path string) string {
+	return fmt.Sprintf(`
+resource "casaos_folder" "test" {
+  path          = %[1]q
+  force_destroy = true
+}
+`, path)
+}
@@ -0,0 +1,315 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GatewaySettingsResource{}
+var _ resource.ResourceWithImportState = &GatewaySettingsResource{}
+var _ resource.ResourceWithModifyPlan = &GatewaySettingsResource{}
+
+func NewGatewaySettingsResource() resource.Resource {
+	return &GatewaySettingsResource{}
+}
+
+// GatewaySettingsResource defines the resource implementation.
+type GatewaySettingsResource struct {
+	client *CasaOSClient
+}
+
+// GatewaySettingsResourceModel describes the resource data model.
+type GatewaySettingsResourceModel struct {
+	Id       types.String   `tfsdk:"id"`
+	Port     types.Int64    `tfsdk:"port"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// gatewaySettingsID is the ID of the only gateway of a device.
+const gatewaySettingsID = "gateway_settings"
+
+// defaultGatewayTimeout covers the gateway restarting on a slow device.
+const defaultGatewayTimeout = 2 * time.Minute
+
+func (r *GatewaySettingsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gateway_settings"
+}
+
+func (r *GatewaySettingsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages the port of the CasaOS gateway, which serves the web UI and the API, for example to free port 80 for a reverse proxy.\n\n" +
+			"When the port changes, the provider waits for the web UI to answer on the new port and uses it for the rest of the apply. " +
+			"Set the `endpoint` of the provider to the new port before the next run. " +
+			"When the provider reaches the device on another port, for example through a reverse proxy, it keeps using its endpoint and does not wait.\n\n" +
+			"A device has a single gateway, so declare this resource at most once per device, also across provider aliases. The provider only detects a second one declared with the same provider configuration. " +
+			"Destroying the resource leaves the gateway on its port.",
+
+		Attributes: map[string]schema.Attribute{
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "Port the web UI and the API listen on.",
+				Required:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Gateway settings identifier, always `gateway_settings`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+func (r *GatewaySettingsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan refuses a second set of gateway settings for the same device,
+// the two would move the gateway back and forth on every apply.
+func (r *GatewaySettingsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	if !r.client.claimSingleton("casaos_gateway_settings") {
+		resp.Diagnostics.AddError(
+			"Duplicate Gateway Settings",
+			fmt.Sprintf("More than one casaos_gateway_settings resource is declared for the CasaOS device at %s. "+
+				"A device has a single gateway, declare the resource only once.", r.client.Endpoint()),
+		)
+	}
+}
+
+func (r *GatewaySettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data GatewaySettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultGatewayTimeout)
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data, createTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(gatewaySettingsID)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GatewaySettingsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data GatewaySettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	port, err := r.gatewayPort(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the gateway port, got error: %s", err))
+		return
+	}
+
+	data.Id = types.StringValue(gatewaySettingsID)
+	data.Port = types.Int64Value(port)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GatewaySettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data GatewaySettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultGatewayTimeout)
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data, updateTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete only removes the resource from state. The previous port is not
+// known, and the gateway needs a port either way.
+func (r *GatewaySettingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data GatewaySettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+}
+
+func (r *GatewaySettingsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != gatewaySettingsID {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("The gateway settings are imported with the ID %q, got %q.", gatewaySettingsID, req.ID))
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// gatewayPort returns the port the gateway listens on.
+func (r *GatewaySettingsResource) gatewayPort(ctx context.Context) (int64, error) {
+	port, err := r.client.GatewayPort(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseInt(port, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("gateway reported invalid port %q", port)
+	}
+
+	return n, nil
+}
+
+// apply moves the gateway to the port data describes and, when the provider
+// reaches the device through the gateway port, points the client at the new
+// port and waits for the gateway to report it from there.
+func (r *GatewaySettingsResource) apply(ctx context.Context, data *GatewaySettingsResourceModel, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	current, err := r.gatewayPort(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read the gateway port, got error: %s", err))
+		return diags
+	}
+
+	if current == data.Port.ValueInt64() {
+		return diags
+	}
+
+	oldPort := strconv.FormatInt(current, 10)
+	port := strconv.FormatInt(data.Port.ValueInt64(), 10)
+
+	if err := r.client.SetGatewayPort(ctx, port); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to move the gateway to port %s, got error: %s", port, err))
+		return diags
+	}
+
+	endpoint, followed := r.client.FollowGatewayPort(oldPort, port)
+
+	tflog.Info(ctx, "moved the CasaOS gateway", map[string]interface{}{
+		"old_port": oldPort,
+		"port":     port,
+		"endpoint": endpoint,
+	})
+
+	if !followed {
+		diags.AddWarning(
+			"Provider Endpoint Not Updated",
+			fmt.Sprintf("The gateway moved from port %s to port %s, but the provider reaches the device at %s, which does not use port %s. "+
+				"The provider keeps using that endpoint. If it forwards to the gateway, for example as a reverse proxy, point it at port %s.",
+				oldPort, port, endpoint, oldPort, port),
+		)
+
+		return diags
+	}
+
+	diags.Append(waitForOperation(ctx, timeout, operation{
+		Description: fmt.Sprintf("restart of the gateway on port %s", port),
+		// The old gateway may still answer until it restarts, only the new
+		// one reports the new port.
+		Poll: func(ctx context.Context) (string, bool, error) {
+			current, err := r.client.GatewayPort(ctx)
+			if err != nil {
+				return "", false, err
+			}
+
+			if current != port {
+				return "listening on port " + current, false, nil
+			}
+
+			return "listening on port " + port, true, nil
+		},
+		Ready: endpoint + "/",
+	})...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.AddWarning(
+		"Provider Endpoint Changed",
+		fmt.Sprintf("The gateway moved from port %s to port %s and the provider uses %s for the rest of this run. "+
+			"Set the endpoint of the provider, or CASAOS_ENDPOINT, to it before the next run.", oldPort, port, endpoint),
+	)
+
+	return diags
+}
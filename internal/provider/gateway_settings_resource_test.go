@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccGatewaySettingsResource(t *testing.T) {
+	var mock *casaosmock.Server
+
+	port, otherPort := testAccFreePort(t), testAccFreePort(t)
+
+	resource.Test(t, resource.TestCase{
+		// Moving the gateway of a device cuts off the tests that follow.
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, resources applied after the move reach
+			// the device on the new port
+			{
+				Config: testAccGatewaySettingsResourceConfig(port) + `
+resource "casaos_app_link" "test" {
+  title = "Router"
+  url   = "http://192.168.1.1"
+
+  depends_on = [casaos_gateway_settings.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_gateway_settings.test", "port", port),
+					resource.TestCheckResourceAttr("casaos_gateway_settings.test", "id", "gateway_settings"),
+					resource.TestCheckResourceAttrSet("casaos_app_link.test", "id"),
+					func(*terraform.State) error {
+						if got := mock.GatewayPort(); got != port {
+							return fmt.Errorf("expected the gateway on port %s, got %s", port, got)
+						}
+
+						// The gateway port, the web UI and the link are read on
+						// the new port.
+						if n := mock.PortRequests(port); n < 3 {
+							return fmt.Errorf("expected the provider to follow the gateway to port %s, got %d requests there", port, n)
+						}
+
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_gateway_settings.test",
+				ImportState:       true,
+				ImportStateId:     "gateway_settings",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing, the provider still reaches the device on
+			// its original port and keeps using it
+			{
+				Config: testAccGatewaySettingsResourceConfig(otherPort),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_gateway_settings.test", "port", otherPort),
+					func(*terraform.State) error {
+						if got := mock.GatewayPort(); got != otherPort {
+							return fmt.Errorf("expected the gateway on port %s, got %s", otherPort, got)
+						}
+
+						if n := mock.PortRequests(otherPort); n != 0 {
+							return fmt.Errorf("expected the provider to keep its endpoint, got %d requests on port %s", n, otherPort)
+						}
+
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccGatewaySettingsResource_Duplicate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGatewaySettingsResourceConfig("8080") + `
+resource "casaos_gateway_settings" "other" {
+  port = 8081
+}
+`,
+				ExpectError: regexp.MustCompile(`More than one casaos_gateway_settings resource is\s+declared`),
+			},
+		},
+	})
+}
+
+func TestAccGatewaySettingsResource_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccGatewaySettingsResourceConfig("70000"),
+				ExpectError: regexp.MustCompile(`value must be between 1 and 65535`),
+			},
+		},
+	})
+}
+
+func TestAccGatewaySettingsResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	port, otherPort := testAccFreePort(t), testAccFreePort(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGatewaySettingsResourceConfig(port),
+			},
+			// A port changed on the dashboard is changed back
+			{
+				PreConfig: func() {
+					if err := mock.SetGatewayPort(otherPort); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccGatewaySettingsResourceConfig(port),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccFreePort returns a local port no one listens on.
+func testAccFreePort(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	return port
+}
+
+func testAccGatewaySettingsResourceConfig(port string) string {
+	return fmt.Sprintf(`
+resource "casaos_gateway_settings" "test" {
+  port = %[1]s
+}
+`, port)
+}
@@ -0,0 +1,527 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &LargeFileResource{}
+var _ resource.ResourceWithModifyPlan = &LargeFileResource{}
+
+func NewLargeFileResource() resource.Resource {
+	return &LargeFileResource{}
+}
+
+// LargeFileResource defines the resource implementation.
+type LargeFileResource struct {
+	client *CasaOSClient
+}
+
+// LargeFileResourceModel describes the resource data model.
+type LargeFileResourceModel struct {
+	ChunkSize types.Int64    `tfsdk:"chunk_size"`
+	Id        types.String   `tfsdk:"id"`
+	Modified  types.String   `tfsdk:"modified"`
+	Path      types.String   `tfsdk:"path"`
+	Sha256    types.String   `tfsdk:"sha256"`
+	Size      types.Int64    `tfsdk:"size"`
+	Source    types.String   `tfsdk:"source"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+}
+
+const (
+	// defaultChunkSize is small enough for a chunk to arrive well within the
+	// HTTP timeout of the client on a slow link.
+	defaultChunkSize = 8 << 20
+
+	// uploadChunkAttempts is how often a chunk is sent before the upload
+	// gives up. A later apply resumes the upload.
+	uploadChunkAttempts = 3
+
+	defaultLargeFileCreateTimeout = 60 * time.Minute
+	defaultLargeFileUpdateTimeout = 60 * time.Minute
+)
+
+// uploadRetryInterval is the pause before the first retry of a failed chunk,
+// it grows with every further attempt. It is a variable so tests can shorten
+// it.
+var uploadRetryInterval = 2 * time.Second
+
+func (r *LargeFileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_large_file"
+}
+
+func (r *LargeFileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Uploads a large local file, such as an ISO image or a database seed, to the device in chunks. " +
+			"Failed chunks are retried, and an upload that fails altogether resumes with the missing chunks on the next apply. " +
+			"Unlike `casaos_file`, the file is never downloaded again: changes on the device are detected through its size and modification time.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the file on the device, for example `/DATA/Downloads/debian.iso`. The folder of the file must exist. An existing file is replaced. Changing this replaces the file.",
+				Required:            true,
+				Validators: []validator.String{
+					absolutePathValidator,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Path of the local file, on the machine running Terraform, to upload. Changes to the local file are detected through `sha256`.",
+				Required:            true,
+			},
+			"chunk_size": schema.Int64Attribute{
+				MarkdownDescription: "Size of the chunks in bytes, between 1 KiB and 1 GiB. Defaults to 8 MiB. Smaller chunks suit slow or unreliable links.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultChunkSize),
+				Validators: []validator.Int64{
+					int64validator.Between(1<<10, 1<<30),
+				},
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "Hex encoded SHA-256 checksum of the uploaded content. Unset when the file on the device no longer has the size and modification time it had after the upload, so the next apply uploads it again.",
+				Computed:            true,
+			},
+			"size": schema.Int64Attribute{
+				MarkdownDescription: "Size of the file on the device in bytes.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"modified": schema.StringAttribute{
+				MarkdownDescription: "Time the file on the device was last modified, in RFC 3339 format.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "File identifier, same as `path`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+func (r *LargeFileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan plans the checksum of the local file. When it differs from the
+// checksum in state, the file is uploaded again and gets a new size and
+// modification time.
+func (r *LargeFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data, state LargeFileResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checksum := types.StringUnknown()
+
+	if !data.Source.IsUnknown() {
+		sum, err := fileSha256(data.Source.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("source"), "Unable to Read Source File", fmt.Sprintf("Unable to read %q: %s", data.Source.ValueString(), err))
+			return
+		}
+
+		checksum = types.StringValue(sum)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), checksum)...)
+
+	if !checksum.Equal(state.Sha256) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("size"), types.Int64Unknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("modified"), types.StringUnknown())...)
+	}
+}
+
+func (r *LargeFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data LargeFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultLargeFileCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.upload(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.Path
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LargeFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data LargeFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	info, err := r.client.StatPath(ctx, data.Path.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "file no longer exists, removing from state", map[string]interface{}{
+			"path": data.Path.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read file %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+
+	size := types.Int64Value(info.Size)
+	modified := types.StringValue(info.Date.UTC().Format(time.RFC3339Nano))
+
+	// Downloading the file to check its content would defeat the purpose of
+	// the resource, a changed size or modification time has to do.
+	if !size.Equal(data.Size) || !modified.Equal(data.Modified) {
+		tflog.Info(ctx, "file changed on the device since it was uploaded", map[string]interface{}{
+			"path":     data.Path.ValueString(),
+			"size":     info.Size,
+			"modified": modified.ValueString(),
+		})
+
+		data.Sha256 = types.StringNull()
+	}
+
+	data.Id = data.Path
+	data.Size = size
+	data.Modified = modified
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LargeFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state LargeFileResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A new chunk size or source path with the same content needs no upload.
+	if !data.Sha256.Equal(state.Sha256) {
+		updateTimeout, diags := data.Timeouts.Update(ctx, defaultLargeFileUpdateTimeout)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+		defer cancel()
+
+		resp.Diagnostics.Append(r.upload(ctx, &data)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LargeFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data LargeFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeletePaths(ctx, data.Path.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete file %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+}
+
+// upload sends the source file to the device chunk by chunk, skipping chunks
+// that arrived in an earlier attempt, and records the checksum, size and
+// modification time of the result in the model.
+func (r *LargeFileResource) upload(ctx context.Context, data *LargeFileResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	source := data.Source.ValueString()
+	destination := data.Path.ValueString()
+
+	f, err := os.Open(source)
+	if err != nil {
+		diags.AddAttributeError(path.Root("source"), "Unable to Read Source File", fmt.Sprintf("Unable to open %q: %s", source, err))
+		return diags
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		diags.AddAttributeError(path.Root("source"), "Unable to Read Source File", fmt.Sprintf("Unable to read %q: %s", source, err))
+		return diags
+	}
+
+	// The checksum was planned from the same file. Hashing it again catches
+	// a source that changed between plan and apply.
+	checksum, err := fileSha256(source)
+	if err != nil {
+		diags.AddAttributeError(path.Root("source"), "Unable to Read Source File", fmt.Sprintf("Unable to read %q: %s", source, err))
+		return diags
+	}
+
+	if !data.Sha256.IsUnknown() && data.Sha256.ValueString() != checksum {
+		diags.AddAttributeError(
+			path.Root("source"),
+			"Source File Changed",
+			fmt.Sprintf("%q changed after the plan was made. Run terraform apply again to upload its current content.", source),
+		)
+
+		return diags
+	}
+
+	upload := NewFileUpload(destination, stat.Size(), data.ChunkSize.ValueInt64(), checksum)
+	total := upload.TotalChunks()
+	chunk := make([]byte, upload.ChunkSize)
+
+	var sent, resumed int64
+
+	lastDecile := int64(-1)
+
+	for number := 1; number <= total; number++ {
+		length := upload.ChunkLength(number)
+
+		var present bool
+
+		err := retryChunk(ctx, number, func() error {
+			var err error
+
+			present, err = r.client.HasUploadChunk(ctx, upload, number)
+
+			return err
+		})
+		if err != nil {
+			addFolderError(&diags, "upload", destination, err)
+			return diags
+		}
+
+		if present {
+			resumed += length
+		} else {
+			if _, err := f.ReadAt(chunk[:length], int64(number-1)*upload.ChunkSize); err != nil && !errors.Is(err, io.EOF) {
+				diags.AddAttributeError(path.Root("source"), "Unable to Read Source File", fmt.Sprintf("Unable to read %q: %s", source, err))
+				return diags
+			}
+
+			err = retryChunk(ctx, number, func() error {
+				return r.client.UploadChunk(ctx, upload, number, chunk[:length])
+			})
+			if err != nil {
+				addFolderError(&diags, "upload", destination, err)
+				return diags
+			}
+
+			sent += length
+		}
+
+		tflog.Debug(ctx, "uploaded chunk", map[string]interface{}{
+			"path":    destination,
+			"chunk":   number,
+			"chunks":  total,
+			"resumed": present,
+		})
+
+		done := sent + resumed
+
+		if upload.TotalSize > 0 && done*10/upload.TotalSize > lastDecile {
+			lastDecile = done * 10 / upload.TotalSize
+
+			tflog.Info(ctx, "uploading a large file", map[string]interface{}{
+				"path":          destination,
+				"percent":       done * 100 / upload.TotalSize,
+				"bytes_done":    done,
+				"bytes_total":   upload.TotalSize,
+				"bytes_resumed": resumed,
+			})
+		}
+	}
+
+	info, err := r.client.StatPath(ctx, destination)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read file %q after uploading it, got error: %s", destination, err))
+		return diags
+	}
+
+	if info.Size != upload.TotalSize {
+		diags.AddError(
+			"Upload Incomplete",
+			fmt.Sprintf("File %q has %d bytes on the device after the upload, expected %d. Run terraform apply again to retry.", destination, info.Size, upload.TotalSize),
+		)
+
+		return diags
+	}
+
+	tflog.Trace(ctx, "uploaded a large file", map[string]interface{}{
+		"path":          destination,
+		"chunks":        total,
+		"bytes_sent":    sent,
+		"bytes_resumed": resumed,
+	})
+
+	data.Sha256 = types.StringValue(checksum)
+	data.Size = types.Int64Value(info.Size)
+	data.Modified = types.StringValue(info.Date.UTC().Format(time.RFC3339Nano))
+
+	return diags
+}
+
+// retryChunk calls send until it succeeds, up to uploadChunkAttempts times.
+// Errors the device answers with a client error status are not retried, they
+// would only fail again.
+func retryChunk(ctx context.Context, number int, send func() error) error {
+	var err error
+
+	for attempt := 1; attempt <= uploadChunkAttempts; attempt++ {
+		if err = send(); err == nil {
+			return nil
+		}
+
+		var apiErr *APIError
+
+		if errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+			return err
+		}
+
+		if attempt == uploadChunkAttempts {
+			break
+		}
+
+		tflog.Warn(ctx, "uploading chunk failed, retrying", map[string]interface{}{
+			"chunk":   number,
+			"attempt": attempt,
+			"error":   err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * uploadRetryInterval):
+		}
+	}
+
+	return fmt.Errorf("chunk %d failed %d times, the next apply resumes the upload: %w", number, uploadChunkAttempts, err)
+}
+
+// fileSha256 returns the hex encoded SHA-256 checksum of a local file without
+// reading it into memory at once.
+func fileSha256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+// testAccLargeFileSource writes a local file of size bytes for upload and
+// returns its path and content.
+func testAccLargeFileSource(t *testing.T, size int, fill byte) (string, []byte) {
+	t.Helper()
+
+	content := bytes.Repeat([]byte{fill}, size)
+	source := filepath.Join(t.TempDir(), "seed.bin")
+
+	if err := os.WriteFile(source, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return source, content
+}
+
+func TestAccLargeFileResource(t *testing.T) {
+	source, content := testAccLargeFileSource(t, 5000, 'a')
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccLargeFileResourceConfig(source, 1024),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_large_file.test", "id", "/DATA/Downloads/tf-acc-seed.bin"),
+					resource.TestCheckResourceAttr("casaos_large_file.test", "sha256", sha256Hex(content)),
+					resource.TestCheckResourceAttr("casaos_large_file.test", "size", "5000"),
+					resource.TestCheckResourceAttrSet("casaos_large_file.test", "modified"),
+				),
+			},
+			// A new chunk size alone uploads nothing
+			{
+				Config: testAccLargeFileResourceConfig(source, 2048),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_large_file.test", "chunk_size", "2048"),
+					resource.TestCheckResourceAttr("casaos_large_file.test", "sha256", sha256Hex(content)),
+				),
+			},
+			// Update and Read testing
+			{
+				PreConfig: func() {
+					content = bytes.Repeat([]byte{'b'}, 3000)
+
+					if err := os.WriteFile(source, content, 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccLargeFileResourceConfig(source, 2048),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_large_file.test", "sha256", sha256Hex(bytes.Repeat([]byte{'b'}, 3000))),
+					resource.TestCheckResourceAttr("casaos_large_file.test", "size", "3000"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccLargeFileResource_Resume(t *testing.T) {
+	var mock *casaosmock.Server
+
+	source, content := testAccLargeFileSource(t, 5000, 'a')
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The third of five chunks keeps failing
+			{
+				PreConfig: func() {
+					mock.AddFault(casaosmock.Fault{
+						Method: http.MethodPost,
+						Path:   "/v1/file/upload",
+						Status: http.StatusBadGateway,
+						After:  2,
+					})
+				},
+				Config:      testAccLargeFileResourceConfig(source, 1024),
+				ExpectError: regexp.MustCompile(`chunk 3 failed\s+3 times`),
+			},
+			// The next apply only sends the missing chunks
+			{
+				PreConfig: func() {
+					mock.ClearFaults()
+				},
+				Config: testAccLargeFileResourceConfig(source, 1024),
+				Check: func(*terraform.State) error {
+					if got, _ := mock.ReadFile("/DATA/Downloads/tf-acc-seed.bin"); !bytes.Equal(got, content) {
+						return fmt.Errorf("expected the resumed upload to produce the source file, got %d bytes", len(got))
+					}
+
+					// 2 chunks and 3 failed attempts first, then the last 3.
+					if n := mock.Requests(http.MethodPost, "/v1/file/upload"); n != 8 {
+						return fmt.Errorf("expected 8 chunk uploads, got %d", n)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccLargeFileResource_Retry(t *testing.T) {
+	var mock *casaosmock.Server
+
+	source, _ := testAccLargeFileSource(t, 3000, 'a')
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					mock.AddFault(casaosmock.Fault{
+						Method: http.MethodPost,
+						Path:   "/v1/file/upload",
+						Status: http.StatusServiceUnavailable,
+						Times:  1,
+						After:  1,
+					})
+				},
+				Config: testAccLargeFileResourceConfig(source, 1024),
+				Check: func(*terraform.State) error {
+					if n := mock.Requests(http.MethodPost, "/v1/file/upload"); n != 4 {
+						return fmt.Errorf("expected 3 chunk uploads and 1 retry, got %d", n)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccLargeFileResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	source, _ := testAccLargeFileSource(t, 3000, 'a')
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLargeFileResourceConfig(source, 1024),
+			},
+			// A file replaced on the device is detected without downloading it
+			{
+				PreConfig: func() {
+					mock.WriteFile("/DATA/Downloads/tf-acc-seed.bin", []byte("replaced"))
+				},
+				Config:             testAccLargeFileResourceConfig(source, 1024),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and uploaded again
+			{
+				Config: testAccLargeFileResourceConfig(source, 1024),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_large_file.test", "size", "3000"),
+					func(*terraform.State) error {
+						if n := mock.Requests(http.MethodGet, "/v1/file"); n != 0 {
+							return fmt.Errorf("expected no downloads, got %d", n)
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccLargeFileResourceConfig(source string, chunkSize int) string {
+	return fmt.Sprintf(`
+resource "casaos_large_file" "test" {
+  path       = "/DATA/Downloads/tf-acc-seed.bin"
+  source     = %[1]q
+  chunk_size = %[2]d
+}
+`, source, chunkSize)
+}
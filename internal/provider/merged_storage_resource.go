@@ -0,0 +1,420 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &MergedStorageResource{}
+var _ resource.ResourceWithImportState = &MergedStorageResource{}
+
+func NewMergedStorageResource() resource.Resource {
+	return &MergedStorageResource{}
+}
+
+// MergedStorageResource defines the resource implementation.
+type MergedStorageResource struct {
+	client *CasaOSClient
+}
+
+// MergedStorageResourceModel describes the resource data model.
+type MergedStorageResourceModel struct {
+	Available  types.Int64  `tfsdk:"available"`
+	Id         types.String `tfsdk:"id"`
+	Members    types.List   `tfsdk:"members"`
+	MountPoint types.String `tfsdk:"mount_point"`
+	Size       types.Int64  `tfsdk:"size"`
+	Used       types.Int64  `tfsdk:"used"`
+}
+
+func (r *MergedStorageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_merged_storage"
+}
+
+func (r *MergedStorageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Pools several storages into one merged storage with [MergerFS](https://github.com/trapexit/mergerfs), so apps see a single folder spanning the disks. " +
+			"Needs a CasaOS version with merged storage support.\n\n" +
+			"When a member is no longer mounted, for example because its disk failed or was unplugged, the plan shows it being added back to the pool. " +
+			"The data on the members is kept when the pool is changed or destroyed.",
+
+		Attributes: map[string]schema.Attribute{
+			"mount_point": schema.StringAttribute{
+				MarkdownDescription: "Absolute path where the pool is mounted, for example `/DATA`.",
+				Required:            true,
+				Validators: []validator.String{
+					absolutePathValidator,
+				},
+			},
+			"members": schema.ListAttribute{
+				MarkdownDescription: "Mount points of the storages to pool, for example `/media/Storage1`. New files go to the first member with enough free space.",
+				ElementType:         types.StringType,
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(absolutePathValidator),
+				},
+			},
+			"size": schema.Int64Attribute{
+				MarkdownDescription: "Capacity of the pool in bytes, the sum of the capacities of the mounted members.",
+				Computed:            true,
+			},
+			"used": schema.Int64Attribute{
+				MarkdownDescription: "Space in use across the mounted members in bytes.",
+				Computed:            true,
+			},
+			"available": schema.Int64Attribute{
+				MarkdownDescription: "Free space across the mounted members in bytes.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Merged storage identifier.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *MergedStorageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *MergedStorageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data MergedStorageResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	merge, diags := data.mergedStorage(ctx)
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checked before the device is changed, an error afterwards would lose
+	// track of the merged storage.
+	resp.Diagnostics.Append(r.checkMembers(ctx, merge)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateMergedStorage(ctx, merge)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create merged storage %q, got error: %s", merge.MountPoint, err))
+		return
+	}
+
+	tflog.Trace(ctx, "created a merged storage", map[string]interface{}{
+		"id":          created.ID,
+		"mount_point": created.MountPoint,
+	})
+
+	// The capacity is only known once the pool is mounted.
+	merged, err := r.client.MergedStorage(ctx, created.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read merged storage %q, got error: %s", merge.MountPoint, err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.setMergedStorage(ctx, &data, merged, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MergedStorageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data MergedStorageResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(data.Id.ValueString(), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Merged Storage ID", fmt.Sprintf("Expected a numeric merged storage ID, got %q.", data.Id.ValueString()))
+		return
+	}
+
+	merge, err := r.client.MergedStorage(ctx, id)
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "merged storage no longer exists, removing from state", map[string]interface{}{
+			"id": id,
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read merged storage %d, got error: %s", id, err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.setMergedStorage(ctx, &data, merge, true)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MergedStorageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data MergedStorageResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	merge, diags := data.mergedStorage(ctx)
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checked before the device is changed, an error afterwards would lose
+	// track of the merged storage.
+	resp.Diagnostics.Append(r.checkMembers(ctx, merge)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UpdateMergedStorage(ctx, merge)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update merged storage %q, got error: %s", merge.MountPoint, err))
+		return
+	}
+
+	// The capacity changes with the members.
+	updated, err := r.client.MergedStorage(ctx, merge.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read merged storage %q, got error: %s", merge.MountPoint, err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.setMergedStorage(ctx, &data, updated, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MergedStorageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data MergedStorageResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(data.Id.ValueString(), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Merged Storage ID", fmt.Sprintf("Expected a numeric merged storage ID, got %q.", data.Id.ValueString()))
+		return
+	}
+
+	err = r.client.DeleteMergedStorage(ctx, id)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete merged storage %q, got error: %s", data.MountPoint.ValueString(), err))
+		return
+	}
+}
+
+func (r *MergedStorageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// setMergedStorage stores merge in data. When prune is set, as on refresh,
+// members that are no longer mounted are left out, so the plan shows them
+// being added back, and reported with a warning. Otherwise the members are
+// stored as the device reports them, matching what was just applied.
+func (r *MergedStorageResource) setMergedStorage(ctx context.Context, data *MergedStorageResourceModel, merge *MergedStorage, prune bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	members := merge.SourceVolumePaths
+
+	if prune {
+		missing, err := r.unmountedMembers(ctx, merge.SourceVolumePaths)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to list storages, got error: %s", err))
+			return diags
+		}
+
+		if len(missing) > 0 {
+			diags.AddWarning(
+				"Merged Storage Degraded",
+				fmt.Sprintf("Merged storage %q is missing members that are no longer mounted: %s. Files on them are not available until they are mounted again.",
+					merge.MountPoint, strings.Join(missing, ", ")),
+			)
+
+			members = withoutMembers(members, missing)
+		}
+	}
+
+	if members == nil {
+		members = []string{}
+	}
+
+	memberList, d := types.ListValueFrom(ctx, types.StringType, members)
+
+	diags.Append(d...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	data.Available = types.Int64Value(merge.Avail)
+	data.Id = types.StringValue(strconv.FormatInt(merge.ID, 10))
+	data.Members = memberList
+	data.MountPoint = types.StringValue(merge.MountPoint)
+	data.Size = types.Int64Value(merge.Size)
+	data.Used = types.Int64Value(merge.Used)
+
+	return diags
+}
+
+// checkMembers reports an error for members of merge that are not mounted,
+// before the merged storage is created or changed.
+func (r *MergedStorageResource) checkMembers(ctx context.Context, merge MergedStorage) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	missing, err := r.unmountedMembers(ctx, merge.SourceVolumePaths)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list storages, got error: %s", err))
+		return diags
+	}
+
+	if len(missing) > 0 {
+		diags.AddAttributeError(
+			path.Root("members"),
+			"Merged Storage Members Not Mounted",
+			fmt.Sprintf("Merged storage %q cannot use %s: not a mounted storage. Mount the members first, for example with casaos_storage.",
+				merge.MountPoint, strings.Join(missing, ", ")),
+		)
+	}
+
+	return diags
+}
+
+// unmountedMembers returns the members that are not mounted storages.
+func (r *MergedStorageResource) unmountedMembers(ctx context.Context, members []string) ([]string, error) {
+	storages, err := r.client.ListStorages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mounted := map[string]bool{}
+
+	for _, storage := range storages {
+		mounted[storage.MountPoint] = true
+	}
+
+	var missing []string
+
+	for _, member := range members {
+		if !mounted[member] {
+			missing = append(missing, member)
+		}
+	}
+
+	return missing, nil
+}
+
+// withoutMembers returns members without the ones in removed.
+func withoutMembers(members []string, removed []string) []string {
+	skip := map[string]bool{}
+
+	for _, member := range removed {
+		skip[member] = true
+	}
+
+	kept := []string{}
+
+	for _, member := range members {
+		if !skip[member] {
+			kept = append(kept, member)
+		}
+	}
+
+	return kept
+}
+
+// mergedStorage returns the merged storage described by m.
+func (m *MergedStorageResourceModel) mergedStorage(ctx context.Context) (MergedStorage, diag.Diagnostics) {
+	merge := MergedStorage{
+		MountPoint: m.MountPoint.ValueString(),
+	}
+
+	if !m.Id.IsUnknown() && !m.Id.IsNull() {
+		merge.ID, _ = strconv.ParseInt(m.Id.ValueString(), 10, 64)
+	}
+
+	diags := m.Members.ElementsAs(ctx, &merge.SourceVolumePaths, false)
+
+	return merge, diags
+}
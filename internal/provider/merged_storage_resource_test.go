@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+// The merged storage tests format a disk for the second member, so they only
+// run against the mock CasaOS.
+
+func TestAccMergedStorageResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccMergedStorageResourceConfig(`"/media/Storage1", casaos_storage.test.mount_point`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "mount_point", "/media/tf-acc-pool"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "members.#", "2"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "members.0", "/media/Storage1"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "members.1", "/media/tf-acc-data"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "size", "6442450944000"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "used", "1288490188800"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "available", "5153960755200"),
+					resource.TestCheckResourceAttrSet("casaos_merged_storage.test", "id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_merged_storage.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccMergedStorageResourceConfig(`"/media/Storage1"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "members.#", "1"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "size", "4294967296000"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccMergedStorageResource_MissingMember(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The members are checked before the device is changed.
+		CheckDestroy: func(*terraform.State) error {
+			if n := mock.Requests(http.MethodPost, "/v2/local_storage/merges"); n != 0 {
+				return fmt.Errorf("expected no merged storage to be created, got %d requests", n)
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMergedStorageResourceConfig(`"/media/Storage1", "/media/tf-acc-missing"`),
+				ExpectError: regexp.MustCompile(`cannot use /media/tf-acc-missing:\s+not\s+a\s+mounted\s+storage`),
+			},
+		},
+	})
+}
+
+func TestAccMergedStorageResource_Unsupported(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccMockOnly(t).AddFault(casaosmock.Fault{
+				Method: http.MethodPost,
+				Path:   "/v2/local_storage/merges",
+				Status: http.StatusNotFound,
+			})
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMergedStorageResourceConfig(`"/media/Storage1"`),
+				ExpectError: regexp.MustCompile(`does not support merged storages`),
+			},
+		},
+	})
+}
+
+func TestAccMergedStorageResource_Degraded(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMergedStorageResourceConfig(`"/media/Storage1", casaos_storage.test.mount_point`),
+			},
+			// A member whose disk disappeared shows up as a change
+			{
+				PreConfig: func() {
+					mock.UnmountStorage("/media/tf-acc-data")
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "members.#", "1"),
+					resource.TestCheckResourceAttr("casaos_merged_storage.test", "size", "4294967296000"),
+				),
+			},
+		},
+	})
+}
+
+func testAccMergedStorageResourceConfig(members string) string {
+	return testAccStorageResourceConfig("/dev/sdc", "tf-acc-data", true) + fmt.Sprintf(`
+resource "casaos_merged_storage" "test" {
+  mount_point = "/media/tf-acc-pool"
+  members     = [%[1]s]
+}
+`, members)
+}
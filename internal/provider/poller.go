@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Backoff between two polls of an operation. The interval starts at
+// pollInitialInterval and doubles up to pollMaxInterval. Tests shorten them.
+var (
+	pollInitialInterval = 1 * time.Second
+	pollMaxInterval     = 15 * time.Second
+)
+
+// logTailLines is how many log lines are shown when an operation fails.
+const logTailLines = 20
+
+// operation describes an asynchronous CasaOS job, such as an app install or a
+// disk format, that a resource waits on.
+type operation struct {
+	// Description names the job in diagnostics, for example
+	// `install of app "jellyfin"`.
+	Description string
+
+	// Poll reports the current status of the job and whether it succeeded.
+	// Server errors and failures to reach the device are retried until the
+	// timeout, any other error ends the wait.
+	Poll func(ctx context.Context) (status string, done bool, err error)
+
+	// Ready optionally names a service that must answer before the job
+	// counts as done: "tcp://host:port" is dialed, an http or https URL must
+	// answer a GET without a server error.
+	Ready string
+
+	// Logs optionally returns the last lines of the job log, shown when the
+	// wait gives up.
+	Logs func(ctx context.Context, lines int) (string, error)
+}
+
+// waitForOperation polls op with exponential backoff until it is done and,
+// when set, its readiness probe answers. It gives up after timeout, or on an
+// error that retrying does not fix, with a diagnostic holding the last known
+// status and the tail of the job log.
+func waitForOperation(ctx context.Context, timeout time.Duration, op operation) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		status   string
+		done     bool
+		pollErr  error
+		probeErr error
+	)
+
+	interval := pollInitialInterval
+	attempts := 0
+
+	for {
+		attempts++
+
+		if !done {
+			s, d, err := op.Poll(waitCtx)
+
+			switch {
+			case err == nil:
+				status, done, pollErr = s, d, nil
+			case waitCtx.Err() != nil:
+				// The timeout is reported below.
+			case isTransient(err):
+				pollErr = err
+
+				tflog.Debug(ctx, "checking operation failed, retrying", map[string]interface{}{
+					"operation": op.Description,
+					"error":     err.Error(),
+				})
+			default:
+				diags.AddError("Client Error", giveUpDetail(ctx, op, fmt.Sprintf("as it could not be checked: %s", err), status, done, nil, nil))
+				return diags
+			}
+		}
+
+		if done && op.Ready != "" {
+			probeErr = probe(waitCtx, op.Ready)
+		}
+
+		if done && (op.Ready == "" || probeErr == nil) {
+			tflog.Debug(ctx, "operation finished", map[string]interface{}{
+				"operation": op.Description,
+				"status":    status,
+				"attempts":  attempts,
+			})
+
+			return diags
+		}
+
+		tflog.Trace(ctx, "waiting for operation", map[string]interface{}{
+			"operation": op.Description,
+			"status":    status,
+			"done":      done,
+			"interval":  interval.String(),
+		})
+
+		select {
+		case <-waitCtx.Done():
+			diags.AddError("Operation Timed Out", giveUpDetail(ctx, op, "after "+timeout.String(), status, done, pollErr, probeErr))
+			return diags
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > pollMaxInterval {
+			interval = pollMaxInterval
+		}
+	}
+}
+
+// giveUpDetail describes why waitForOperation gave up, the reason completing
+// "Gave up waiting for the job". ctx must not be the expired wait context, so
+// the log tail can still be fetched.
+func giveUpDetail(ctx context.Context, op operation, reason string, status string, done bool, pollErr error, probeErr error) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Gave up waiting for the %s %s.", op.Description, reason)
+
+	if status == "" {
+		b.WriteString(" No status was reported.")
+	} else {
+		fmt.Fprintf(&b, " Last known status: %q.", status)
+	}
+
+	if pollErr != nil {
+		fmt.Fprintf(&b, " The last check failed: %s.", pollErr)
+	}
+
+	if done && probeErr != nil {
+		fmt.Fprintf(&b, " The job finished but %s did not answer: %s.", op.Ready, probeErr)
+	}
+
+	if op.Logs != nil {
+		logCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		logs, err := op.Logs(logCtx, logTailLines)
+
+		switch {
+		case err != nil:
+			fmt.Fprintf(&b, "\n\nThe log could not be read: %s", err)
+		case strings.TrimSpace(logs) != "":
+			fmt.Fprintf(&b, "\n\nLast %d log lines:\n%s", logTailLines, tailLines(logs, logTailLines))
+		}
+	}
+
+	b.WriteString("\n\nThe job may still finish on the device. Increase the timeout in the resource's timeouts block if it regularly needs more time.")
+
+	return b.String()
+}
+
+// probe checks once whether the service at address answers.
+func probe(ctx context.Context, address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid readiness address %q: %w", address, err)
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		var d net.Dialer
+
+		conn, err := d.DialContext(ctx, "tcp", u.Host)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("invalid readiness address %q: scheme must be tcp, http or https", address)
+	}
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n")
+}
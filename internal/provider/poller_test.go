@@ -0,0 +1,252 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fastPolling shortens the poll backoff for the duration of a test.
+func fastPolling(t *testing.T) {
+	t.Helper()
+
+	initial, maximum := pollInitialInterval, pollMaxInterval
+	pollInitialInterval, pollMaxInterval = time.Millisecond, 5*time.Millisecond
+
+	t.Cleanup(func() {
+		pollInitialInterval, pollMaxInterval = initial, maximum
+	})
+}
+
+func TestWaitForOperation_Success(t *testing.T) {
+	fastPolling(t)
+
+	polls := 0
+
+	diags := waitForOperation(context.Background(), time.Second, operation{
+		Description: "test job",
+		Poll: func(ctx context.Context) (string, bool, error) {
+			polls++
+			return "installing", polls == 3, nil
+		},
+	})
+
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestWaitForOperation_Timeout(t *testing.T) {
+	fastPolling(t)
+
+	diags := waitForOperation(context.Background(), 20*time.Millisecond, operation{
+		Description: "install of app \"test\"",
+		Poll: func(ctx context.Context) (string, bool, error) {
+			return "stopped", false, nil
+		},
+		Logs: func(ctx context.Context, lines int) (string, error) {
+			return strings.Repeat("noise\n", 50) + "panic: missing config\n", nil
+		},
+	})
+
+	if !diags.HasError() {
+		t.Fatal("expected a timeout diagnostic")
+	}
+
+	detail := diags[0].Detail()
+
+	for _, want := range []string{`install of app "test"`, `Last known status: "stopped"`, "panic: missing config"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("expected detail to contain %q, got:\n%s", want, detail)
+		}
+	}
+
+	if n := strings.Count(detail, "noise"); n != logTailLines-1 {
+		t.Errorf("expected the log tail to be cut to %d lines, got %d noise lines", logTailLines, n)
+	}
+}
+
+func TestWaitForOperation_PollError(t *testing.T) {
+	fastPolling(t)
+
+	polls := 0
+
+	diags := waitForOperation(context.Background(), time.Second, operation{
+		Description: "test job",
+		Poll: func(ctx context.Context) (string, bool, error) {
+			polls++
+
+			if polls == 1 {
+				return "pulling", false, nil
+			}
+
+			return "", false, errors.New("boom")
+		},
+		Logs: func(ctx context.Context, lines int) (string, error) {
+			return "pull access denied\n", nil
+		},
+	})
+
+	if !diags.HasError() {
+		t.Fatal("expected the poll error to be reported")
+	}
+
+	detail := diags[0].Detail()
+
+	for _, want := range []string{"boom", `Last known status: "pulling"`, "pull access denied"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("expected detail to contain %q, got:\n%s", want, detail)
+		}
+	}
+
+	if polls != 2 {
+		t.Errorf("expected the wait to stop after the first error, got %d polls", polls)
+	}
+}
+
+func TestWaitForOperation_TransientError(t *testing.T) {
+	fastPolling(t)
+
+	polls := 0
+
+	diags := waitForOperation(context.Background(), time.Second, operation{
+		Description: "test job",
+		Poll: func(ctx context.Context) (string, bool, error) {
+			polls++
+
+			if polls < 3 {
+				return "", false, &APIError{StatusCode: http.StatusBadGateway}
+			}
+
+			return "running", true, nil
+		},
+	})
+
+	if diags.HasError() {
+		t.Fatalf("expected server errors to be retried, got: %v", diags)
+	}
+
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestWaitForOperation_TransientErrorTimeout(t *testing.T) {
+	fastPolling(t)
+
+	polls := 0
+
+	diags := waitForOperation(context.Background(), 20*time.Millisecond, operation{
+		Description: "test job",
+		Poll: func(ctx context.Context) (string, bool, error) {
+			polls++
+
+			if polls == 1 {
+				return "pulling", false, nil
+			}
+
+			return "", false, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		},
+		Logs: func(ctx context.Context, lines int) (string, error) {
+			return "restarting\n", nil
+		},
+	})
+
+	if !diags.HasError() {
+		t.Fatal("expected a timeout diagnostic")
+	}
+
+	detail := diags[0].Detail()
+
+	for _, want := range []string{`Last known status: "pulling"`, "connection refused", "restarting"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("expected detail to contain %q, got:\n%s", want, detail)
+		}
+	}
+}
+
+func TestWaitForOperation_ReadyHTTP(t *testing.T) {
+	fastPolling(t)
+
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// The app answers with errors while it is starting up.
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	diags := waitForOperation(context.Background(), time.Second, operation{
+		Description: "test job",
+		Poll: func(ctx context.Context) (string, bool, error) {
+			return "running", true, nil
+		},
+		Ready: server.URL,
+	})
+
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 readiness requests, got %d", requests)
+	}
+}
+
+func TestWaitForOperation_ReadyTCPTimeout(t *testing.T) {
+	fastPolling(t)
+
+	// Grab a free port and close it again, so nothing listens on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := listener.Addr().String()
+	listener.Close()
+
+	diags := waitForOperation(context.Background(), 20*time.Millisecond, operation{
+		Description: "test job",
+		Poll: func(ctx context.Context) (string, bool, error) {
+			return "running", true, nil
+		},
+		Ready: "tcp://" + address,
+	})
+
+	if !diags.HasError() {
+		t.Fatal("expected a timeout diagnostic")
+	}
+
+	if detail := diags[0].Detail(); !strings.Contains(detail, "did not answer") {
+		t.Errorf("expected detail to mention the readiness probe, got:\n%s", detail)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	if got := tailLines("a\nb\nc\n", 2); got != "b\nc" {
+		t.Errorf("expected %q, got %q", "b\nc", got)
+	}
+
+	if got := tailLines("a", 5); got != "a" {
+		t.Errorf("expected %q, got %q", "a", got)
+	}
+}
@@ -5,14 +5,16 @@ package provider
 
 import (
 	"context"
-	"net/http"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
@@ -30,19 +32,32 @@ type ScaffoldingProvider struct {
 // ScaffoldingProviderModel describes the provider data model.
 type ScaffoldingProviderModel struct {
 	Endpoint types.String `tfsdk:"endpoint"`
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
 }
 
 func (p *ScaffoldingProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
-	resp.TypeName = "scaffolding"
+	resp.TypeName = "casaos"
 	resp.Version = p.version
 }
 
 func (p *ScaffoldingProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manage apps, storage and settings of a [CasaOS](https://casaos.io) device through its HTTP API.",
+
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "Example provider attribute",
+				MarkdownDescription: "Base URL of the CasaOS web UI, for example `http://casaos.local`. May also be provided via the `CASAOS_ENDPOINT` environment variable.",
+				Optional:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Username of the CasaOS account to log in with. May also be provided via the `CASAOS_USERNAME` environment variable.",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password of the CasaOS account to log in with. May also be provided via the `CASAOS_PASSWORD` environment variable.",
 				Optional:            true,
+				Sensitive:           true,
 			},
 		},
 	}
@@ -57,24 +72,153 @@ func (p *ScaffoldingProvider) Configure(ctx context.Context, req provider.Config
 		return
 	}
 
-	// Configuration values are now available.
-	// if data.Endpoint.IsNull() { /* ... */ }
+	// Unknown values cannot be used to log in, so fail early with a hint on
+	// where the value is coming from.
+	if data.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unknown CasaOS API Endpoint",
+			"The provider cannot create the CasaOS API client as there is an unknown configuration value for the CasaOS API endpoint. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CASAOS_ENDPOINT environment variable.",
+		)
+	}
+
+	if data.Username.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Unknown CasaOS API Username",
+			"The provider cannot create the CasaOS API client as there is an unknown configuration value for the CasaOS API username. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CASAOS_USERNAME environment variable.",
+		)
+	}
+
+	if data.Password.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Unknown CasaOS API Password",
+			"The provider cannot create the CasaOS API client as there is an unknown configuration value for the CasaOS API password. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CASAOS_PASSWORD environment variable.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Configuration values take precedence over environment variables.
+	endpoint := os.Getenv("CASAOS_ENDPOINT")
+	username := os.Getenv("CASAOS_USERNAME")
+	password := os.Getenv("CASAOS_PASSWORD")
+
+	if !data.Endpoint.IsNull() {
+		endpoint = data.Endpoint.ValueString()
+	}
+
+	if !data.Username.IsNull() {
+		username = data.Username.ValueString()
+	}
+
+	if !data.Password.IsNull() {
+		password = data.Password.ValueString()
+	}
+
+	if endpoint == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Missing CasaOS API Endpoint",
+			"The provider cannot create the CasaOS API client as there is a missing or empty value for the CasaOS API endpoint. "+
+				"Set the endpoint value in the configuration or use the CASAOS_ENDPOINT environment variable.",
+		)
+	}
+
+	if username == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Missing CasaOS API Username",
+			"The provider cannot create the CasaOS API client as there is a missing or empty value for the CasaOS API username. "+
+				"Set the username value in the configuration or use the CASAOS_USERNAME environment variable.",
+		)
+	}
+
+	if password == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Missing CasaOS API Password",
+			"The provider cannot create the CasaOS API client as there is a missing or empty value for the CasaOS API password. "+
+				"Set the password value in the configuration or use the CASAOS_PASSWORD environment variable.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := NewCasaOSClient(endpoint, username, password)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Invalid CasaOS API Endpoint",
+			err.Error(),
+		)
+
+		return
+	}
+
+	// A freshly installed device has no account to log in with until a
+	// casaos_user resource registers the owner. Devices too old to report
+	// their status are logged in to as usual.
+	status, err := client.UserStatus(ctx)
+
+	if err == nil && !status.Initialized {
+		tflog.Info(ctx, "CasaOS has no owner account yet, skipping login", map[string]interface{}{
+			"endpoint": endpoint,
+		})
+	} else if err := client.Login(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Log In to CasaOS",
+			"An unexpected error occurred when logging in to the CasaOS API. "+
+				"Check the endpoint and credentials, and that the device is reachable.\n\n"+
+				"CasaOS Client Error: "+err.Error(),
+		)
+
+		return
+	}
 
-	// Example client configuration for data sources and resources
-	client := http.DefaultClient
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
 func (p *ScaffoldingProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
-		NewExampleResource,
+		NewAppResource,
+		NewAppStoreAppResource,
+		NewAppStoreResource,
+		NewAppStateResource,
+		NewSambaShareResource,
+		NewSambaConnectionResource,
+		NewFolderResource,
+		NewFileResource,
+		NewLargeFileResource,
+		NewUserResource,
+		NewStorageResource,
+		NewMergedStorageResource,
+		NewAppGridResource,
+		NewAppLinkResource,
+		NewUserCustomDataResource,
+		NewDashboardWidgetsResource,
+		NewGatewaySettingsResource,
 	}
 }
 
 func (p *ScaffoldingProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		NewExampleDataSource,
+		NewAppsDataSource,
+		NewAppDataSource,
+		NewAppStoreCatalogDataSource,
+		NewDirectoryListingDataSource,
+		NewSystemInfoDataSource,
+		NewDisksDataSource,
+		NewStorageDataSource,
 	}
 }
 
@@ -4,22 +4,89 @@
 package provider
 
 import (
+	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
 // acceptance testing. The factory function will be invoked for every Terraform
 // CLI command executed to create a provider server to which the CLI can
 // reattach.
+//
+// The provider reads its connection settings from the environment, which
+// testAccPreCheck points at a mock CasaOS unless a real device is configured.
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"scaffolding": providerserver.NewProtocol6WithError(New("test")()),
+	"casaos": providerserver.NewProtocol6WithError(New("test")()),
 }
 
+// testAccMocks holds the mock CasaOS of every running acceptance test.
+var testAccMocks sync.Map
+
 func testAccPreCheck(t *testing.T) {
-	// You can add code here to run prior to any test case execution, for example assertions
-	// about the appropriate environment variables being set are common to see in a pre-check
-	// function.
+	testAccMock(t)
+}
+
+// testAccMock points the provider at a fresh mock CasaOS for the duration of
+// the test and returns it. When CASAOS_ENDPOINT is set the test runs against
+// that device instead and testAccMock returns nil.
+func testAccMock(t *testing.T) *casaosmock.Server {
+	t.Helper()
+
+	if mock, ok := testAccMocks.Load(t); ok {
+		return mock.(*casaosmock.Server)
+	}
+
+	if os.Getenv("CASAOS_ENDPOINT") != "" {
+		for _, name := range []string{"CASAOS_USERNAME", "CASAOS_PASSWORD"} {
+			if os.Getenv(name) == "" {
+				t.Fatalf("%s must be set for acceptance tests against a device", name)
+			}
+		}
+
+		return nil
+	}
+
+	mock := casaosmock.NewServer()
+
+	// Poll the mock quickly, it finishes every job right away, and retry
+	// injected upload failures right away.
+	initial, maximum, retry := pollInitialInterval, pollMaxInterval, uploadRetryInterval
+	pollInitialInterval, pollMaxInterval = 10*time.Millisecond, 100*time.Millisecond
+	uploadRetryInterval = 10 * time.Millisecond
+
+	t.Setenv("CASAOS_ENDPOINT", mock.URL)
+	t.Setenv("CASAOS_USERNAME", casaosmock.Username)
+	t.Setenv("CASAOS_PASSWORD", casaosmock.Password)
+
+	testAccMocks.Store(t, mock)
+
+	t.Cleanup(func() {
+		testAccMocks.Delete(t)
+		mock.Close()
+
+		pollInitialInterval, pollMaxInterval = initial, maximum
+		uploadRetryInterval = retry
+	})
+
+	return mock
+}
+
+// testAccMockOnly returns the mock CasaOS of the test, skipping the test when
+// it runs against a real device.
+func testAccMockOnly(t *testing.T) *casaosmock.Server {
+	t.Helper()
+
+	mock := testAccMock(t)
+	if mock == nil {
+		t.Skip("this test injects faults and only runs against the mock CasaOS")
+	}
+
+	return mock
 }
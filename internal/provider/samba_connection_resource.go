@@ -0,0 +1,295 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SambaConnectionResource{}
+
+func NewSambaConnectionResource() resource.Resource {
+	return &SambaConnectionResource{}
+}
+
+// SambaConnectionResource defines the resource implementation.
+type SambaConnectionResource struct {
+	client *CasaOSClient
+}
+
+// SambaConnectionResourceModel describes the resource data model.
+type SambaConnectionResourceModel struct {
+	Host       types.String `tfsdk:"host"`
+	Id         types.String `tfsdk:"id"`
+	MountPoint types.String `tfsdk:"mount_point"`
+	Password   types.String `tfsdk:"password"`
+	Port       types.Int64  `tfsdk:"port"`
+	Username   types.String `tfsdk:"username"`
+}
+
+func (r *SambaConnectionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_samba_connection"
+}
+
+func (r *SambaConnectionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Connects the device to a remote SMB server, such as a NAS, and mounts its shares, like \"Connect to network storage\" on the dashboard. CasaOS cannot change a connection in place, so every change replaces it.",
+
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				MarkdownDescription: "Host name or IP address of the SMB server, as reachable from the device. Changing this replaces the connection.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "Port of the SMB server. Defaults to `445`. Changing this replaces the connection.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(445),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "User to log in to the SMB server as. Changing this replaces the connection.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password of `username`. CasaOS does not return it, so a password changed outside Terraform is not detected. Changing this replaces the connection.",
+				Required:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mount_point": schema.StringAttribute{
+				MarkdownDescription: "Folder on the device the shares of the server are mounted under.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID the device assigned to the connection.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SambaConnectionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SambaConnectionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SambaConnectionResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	address := fmt.Sprintf("%s:%d", data.Host.ValueString(), data.Port.ValueInt64())
+
+	connection, err := r.client.CreateSambaConnection(ctx, SambaConnection{
+		Host:     data.Host.ValueString(),
+		Port:     strconv.FormatInt(data.Port.ValueInt64(), 10),
+		Username: data.Username.ValueString(),
+		Password: data.Password.ValueString(),
+	})
+
+	// The device connects to the server while creating the connection.
+	var apiErr *APIError
+
+	if errors.As(err, &apiErr) && isSMBUnreachable(apiErr) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("host"),
+			"Unable to Connect to SMB Server",
+			fmt.Sprintf("CasaOS could not reach the SMB server at %s: %s\n\n"+
+				"Check that the server is reachable from the device, not just from where Terraform runs, and that it accepts SMB connections on port %d.",
+				address, apiErr.Message, data.Port.ValueInt64()),
+		)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to connect to SMB server %s, got error: %s", address, err))
+		return
+	}
+
+	data.Id = types.StringValue(strconv.FormatInt(connection.ID, 10))
+	data.MountPoint = types.StringValue(connection.MountPoint)
+
+	tflog.Trace(ctx, "created an SMB connection", map[string]interface{}{
+		"id":          connection.ID,
+		"address":     address,
+		"mount_point": connection.MountPoint,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SambaConnectionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SambaConnectionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(data.Id.ValueString(), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Connection ID", fmt.Sprintf("Expected a numeric SMB connection ID, got %q.", data.Id.ValueString()))
+		return
+	}
+
+	connection, err := r.client.SambaConnection(ctx, id)
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "SMB connection no longer exists, removing from state", map[string]interface{}{
+			"id": id,
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read SMB connection %d, got error: %s", id, err))
+		return
+	}
+
+	port, err := strconv.ParseInt(connection.Port, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("SMB connection %d has an invalid port %q.", id, connection.Port))
+		return
+	}
+
+	// The password is not returned and stays as configured.
+	data.Host = types.StringValue(connection.Host)
+	data.Port = types.Int64Value(port)
+	data.Username = types.StringValue(connection.Username)
+	data.MountPoint = types.StringValue(connection.MountPoint)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SambaConnectionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SambaConnectionResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every configurable attribute requires replacement, so there is nothing
+	// to change on the device.
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SambaConnectionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SambaConnectionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(data.Id.ValueString(), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Connection ID", fmt.Sprintf("Expected a numeric SMB connection ID, got %q.", data.Id.ValueString()))
+		return
+	}
+
+	err = r.client.DeleteSambaConnection(ctx, id)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete SMB connection %d, got error: %s", id, err))
+		return
+	}
+}
+
+// smbUnreachableErrors are the network errors CasaOS passes on when it cannot
+// reach an SMB server.
+var smbUnreachableErrors = []string{
+	"no such host",
+	"connection refused",
+	"no route to host",
+	"network is unreachable",
+	"i/o timeout",
+}
+
+// isSMBUnreachable reports whether apiErr says that CasaOS could not reach the
+// SMB server, as opposed to the server rejecting the login or CasaOS failing.
+func isSMBUnreachable(apiErr *APIError) bool {
+	if apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	for _, message := range smbUnreachableErrors {
+		if strings.Contains(apiErr.Message, message) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+// The tests that connect successfully need an SMB server, so they only run
+// against the mock CasaOS.
+
+func TestAccSambaConnectionResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccSambaConnectionResourceConfig("nas.example.com", "secret"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_samba_connection.test", "host", "nas.example.com"),
+					resource.TestCheckResourceAttr("casaos_samba_connection.test", "port", "445"),
+					resource.TestCheckResourceAttr("casaos_samba_connection.test", "username", "tf-acc"),
+					resource.TestCheckResourceAttr("casaos_samba_connection.test", "mount_point", "/mnt/nas.example.com"),
+					resource.TestCheckResourceAttrSet("casaos_samba_connection.test", "id"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: testAccSambaConnectionResourceConfig("backup.example.com", "secret"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_samba_connection.test", "host", "backup.example.com"),
+					resource.TestCheckResourceAttr("casaos_samba_connection.test", "mount_point", "/mnt/backup.example.com"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccSambaConnectionResource_Unreachable(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSambaConnectionResourceConfig("nas.invalid", "secret"),
+				ExpectError: regexp.MustCompile(`Unable to Connect to SMB Server`),
+			},
+		},
+	})
+}
+
+func TestAccSambaConnectionResource_WrongPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSambaConnectionResourceConfig("nas.example.com", "wrong"),
+				ExpectError: regexp.MustCompile(`NT_STATUS_LOGON_FAILURE`),
+			},
+		},
+	})
+}
+
+func TestAccSambaConnectionResource_ServerError(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A failing CasaOS is not reported as an unreachable server
+			{
+				PreConfig: func() {
+					mock.AddFault(casaosmock.Fault{
+						Method: http.MethodPost,
+						Path:   "/v1/samba/connections",
+						Status: http.StatusInternalServerError,
+					})
+				},
+				Config:      testAccSambaConnectionResourceConfig("nas.example.com", "secret"),
+				ExpectError: regexp.MustCompile(`Client Error(.|\n)*HTTP 500`),
+			},
+		},
+	})
+}
+
+func TestAccSambaConnectionResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSambaConnectionResourceConfig("nas.example.com", "secret"),
+			},
+			// A connection removed from the dashboard is created again
+			{
+				PreConfig: func() {
+					mock.RemoveSambaConnection("nas.example.com")
+				},
+				Config:             testAccSambaConnectionResourceConfig("nas.example.com", "secret"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccSambaConnectionResourceConfig(host string, password string) string {
+	return fmt.Sprintf(`
+resource "casaos_samba_connection" "test" {
+  host     = %[1]q
+  username = "tf-acc"
+  password = %[2]q
+}
+`, host, password)
+}
@@ -0,0 +1,256 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SambaShareResource{}
+var _ resource.ResourceWithImportState = &SambaShareResource{}
+
+func NewSambaShareResource() resource.Resource {
+	return &SambaShareResource{}
+}
+
+// SambaShareResource defines the resource implementation.
+type SambaShareResource struct {
+	client *CasaOSClient
+}
+
+// SambaShareResourceModel describes the resource data model.
+type SambaShareResourceModel struct {
+	Anonymous types.Bool   `tfsdk:"anonymous"`
+	Id        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Path      types.String `tfsdk:"path"`
+}
+
+func (r *SambaShareResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_samba_share"
+}
+
+func (r *SambaShareResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Shares a folder of the device over SMB. CasaOS cannot change a share in place, so every change replaces it.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name clients see the share under. Changing this replaces the share.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^/\\\[\]:|<>+=;,*?"]+$`), "must be a valid SMB share name"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the shared folder on the device, for example `/DATA/Media`. The folder must exist when the share is created. Changing this replaces the share.",
+				Required:            true,
+				Validators: []validator.String{
+					absolutePathValidator,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"anonymous": schema.BoolAttribute{
+				MarkdownDescription: "Whether guests can access the share without logging in. Defaults to `false`. Changing this replaces the share.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Share identifier, same as `name`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SambaShareResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SambaShareResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SambaShareResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check the folder first so a wrong path gets a clear diagnostic. The
+	// folder may be created earlier in the same apply, so this cannot be
+	// checked at plan time.
+	_, err := r.client.ListFolder(ctx, data.Path.ValueString())
+	if IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Share Folder Not Found",
+			fmt.Sprintf("Folder %q does not exist on the device. Create it before sharing it.", data.Path.ValueString()),
+		)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to check folder %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+
+	err = r.client.CreateSambaShare(ctx, SambaShare{
+		Name:      data.Name.ValueString(),
+		Path:      data.Path.ValueString(),
+		Anonymous: data.Anonymous.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create SMB share %q, got error: %s", data.Name.ValueString(), err))
+		return
+	}
+
+	share, err := r.client.SambaShareByName(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read SMB share %q after creating it, got error: %s", data.Name.ValueString(), err))
+		return
+	}
+
+	data.setSambaShare(share)
+
+	tflog.Trace(ctx, "created an SMB share", map[string]interface{}{
+		"name": share.Name,
+		"path": share.Path,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SambaShareResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SambaShareResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	share, err := r.client.SambaShareByName(ctx, data.Id.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "SMB share no longer exists, removing from state", map[string]interface{}{
+			"name": data.Id.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read SMB share %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.setSambaShare(share)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SambaShareResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SambaShareResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every configurable attribute requires replacement, so there is nothing
+	// to change on the device.
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SambaShareResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SambaShareResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	share, err := r.client.SambaShareByName(ctx, data.Id.ValueString())
+	if IsNotFound(err) {
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read SMB share %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	err = r.client.DeleteSambaShare(ctx, share.ID)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete SMB share %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+}
+
+func (r *SambaShareResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (m *SambaShareResourceModel) setSambaShare(share *SambaShare) {
+	m.Id = types.StringValue(share.Name)
+	m.Name = types.StringValue(share.Name)
+	m.Path = types.StringValue(share.Path)
+	m.Anonymous = types.BoolValue(share.Anonymous)
+}
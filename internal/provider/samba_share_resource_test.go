@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccSambaShareResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccSambaShareResourceConfig("/DATA/Media", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_samba_share.test", "id", "tf-acc-share"),
+					resource.TestCheckResourceAttr("casaos_samba_share.test", "name", "tf-acc-share"),
+					resource.TestCheckResourceAttr("casaos_samba_share.test", "path", "/DATA/Media"),
+					resource.TestCheckResourceAttr("casaos_samba_share.test", "anonymous", "false"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_samba_share.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccSambaShareResourceConfig("/DATA/Documents", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_samba_share.test", "path", "/DATA/Documents"),
+					resource.TestCheckResourceAttr("casaos_samba_share.test", "anonymous", "true"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccSambaShareResource_MissingFolder(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSambaShareResourceConfig("/DATA/tf-acc-does-not-exist", false),
+				ExpectError: regexp.MustCompile(`Share Folder Not Found`),
+			},
+		},
+	})
+}
+
+func TestAccSambaShareResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSambaShareResourceConfig("/DATA/Media", false),
+			},
+			// A share removed from the dashboard is created again
+			{
+				PreConfig: func() {
+					mock.RemoveSambaShare("tf-acc-share")
+				},
+				Config:             testAccSambaShareResourceConfig("/DATA/Media", false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccSambaShareResourceConfig(path string, anonymous bool) string {
+	return fmt.Sprintf(`
+resource "casaos_samba_share" "test" {
+  name      = "tf-acc-share"
+  path      = %[1]q
+  anonymous = %[2]t
+}
+`, path, anonymous)
+}
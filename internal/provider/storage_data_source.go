@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &StorageDataSource{}
+
+func NewStorageDataSource() datasource.DataSource {
+	return &StorageDataSource{}
+}
+
+// StorageDataSource defines the data source implementation.
+type StorageDataSource struct {
+	client *CasaOSClient
+}
+
+// StorageDataSourceModel describes the data source data model.
+type StorageDataSourceModel struct {
+	Storages []storageModel `tfsdk:"storages"`
+}
+
+// storageModel describes a storage of the device.
+type storageModel struct {
+	Available  types.Int64  `tfsdk:"available"`
+	Disk       types.String `tfsdk:"disk"`
+	Filesystem types.String `tfsdk:"filesystem"`
+	MountPoint types.String `tfsdk:"mount_point"`
+	Name       types.String `tfsdk:"name"`
+	Partition  types.String `tfsdk:"partition"`
+	Size       types.Int64  `tfsdk:"size"`
+	Used       types.Int64  `tfsdk:"used"`
+}
+
+func (d *StorageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_storage"
+}
+
+func (d *StorageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists the storages CasaOS has mounted on the disks of the device with their free and used space, for example to refuse installing a large app onto a nearly full disk.",
+
+		Attributes: map[string]schema.Attribute{
+			"storages": schema.ListNestedAttribute{
+				MarkdownDescription: "Storages, sorted by mount point.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the storage shown on the dashboard.",
+							Computed:            true,
+						},
+						"mount_point": schema.StringAttribute{
+							MarkdownDescription: "Where the storage is mounted, for example `/media/Storage1`.",
+							Computed:            true,
+						},
+						"partition": schema.StringAttribute{
+							MarkdownDescription: "Device path of the mounted partition, for example `/dev/sdb1`.",
+							Computed:            true,
+						},
+						"disk": schema.StringAttribute{
+							MarkdownDescription: "Device path of the disk the partition is on, for example `/dev/sdb`.",
+							Computed:            true,
+						},
+						"filesystem": schema.StringAttribute{
+							MarkdownDescription: "Filesystem of the storage, for example `ext4`.",
+							Computed:            true,
+						},
+						"size": schema.Int64Attribute{
+							MarkdownDescription: "Capacity of the storage in bytes.",
+							Computed:            true,
+						},
+						"used": schema.Int64Attribute{
+							MarkdownDescription: "Space in use in bytes.",
+							Computed:            true,
+						},
+						"available": schema.Int64Attribute{
+							MarkdownDescription: "Free space in bytes. Filesystems reserve some space, so `used` and `available` need not add up to `size`.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *StorageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *StorageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data StorageDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	storages, err := d.client.ListStorages(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list storages, got error: %s", err))
+		return
+	}
+
+	sort.Slice(storages, func(i, j int) bool {
+		return storages[i].MountPoint < storages[j].MountPoint
+	})
+
+	data.Storages = make([]storageModel, 0, len(storages))
+
+	for _, storage := range storages {
+		data.Storages = append(data.Storages, storageModel{
+			Available:  types.Int64Value(storage.Avail),
+			Disk:       types.StringValue(storage.Disk),
+			Filesystem: types.StringValue(storage.Type),
+			MountPoint: types.StringValue(storage.MountPoint),
+			Name:       types.StringValue(storage.Name),
+			Partition:  types.StringValue(storage.Path),
+			Size:       types.Int64Value(storage.Size),
+			Used:       types.Int64Value(storage.Used),
+		})
+	}
+
+	tflog.Trace(ctx, "read storages", map[string]interface{}{
+		"count": len(data.Storages),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccStorageDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccStorageDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.#", "1"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.name", "Storage1"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.mount_point", "/media/Storage1"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.partition", "/dev/sdb1"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.disk", "/dev/sdb"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.filesystem", "ext4"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.size", "4294967296000"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.used", "1288490188800"),
+					resource.TestCheckResourceAttr("data.casaos_storage.test", "storages.0.available", "3006477107200"),
+				),
+			},
+		},
+	})
+}
+
+const testAccStorageDataSourceConfig = `
+data "casaos_storage" "test" {}
+`
@@ -0,0 +1,481 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StorageResource{}
+var _ resource.ResourceWithImportState = &StorageResource{}
+var _ resource.ResourceWithModifyPlan = &StorageResource{}
+var _ resource.ResourceWithValidateConfig = &StorageResource{}
+
+func NewStorageResource() resource.Resource {
+	return &StorageResource{}
+}
+
+// StorageResource defines the resource implementation.
+type StorageResource struct {
+	client *CasaOSClient
+}
+
+// StorageResourceModel describes the resource data model.
+type StorageResourceModel struct {
+	AllowFormat types.Bool     `tfsdk:"allow_format"`
+	Disk        types.String   `tfsdk:"disk"`
+	Filesystem  types.String   `tfsdk:"filesystem"`
+	Id          types.String   `tfsdk:"id"`
+	MountPoint  types.String   `tfsdk:"mount_point"`
+	Name        types.String   `tfsdk:"name"`
+	Partition   types.String   `tfsdk:"partition"`
+	Size        types.Int64    `tfsdk:"size"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+// devicePathValidator checks that a disk is given by its device path.
+var devicePathValidator = stringvalidator.RegexMatches(regexp.MustCompile(`^/dev/\S+$`), "must be a device path such as /dev/sdb")
+
+// defaultStorageCreateTimeout covers formatting a large hard disk.
+const defaultStorageCreateTimeout = 10 * time.Minute
+
+func (r *StorageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_storage"
+}
+
+func (r *StorageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Formats a disk of the device and mounts it as a CasaOS storage, like \"Create Storage\" on the dashboard.\n\n" +
+			"~> **Creating this resource erases the whole disk.** `allow_format` must be set to `true`, and the plan warns when the disk has partitions. " +
+			"Destroying the resource only unmounts the storage, the data stays on the disk. " +
+			"Every change other than `allow_format` formats the disk again.",
+
+		Attributes: map[string]schema.Attribute{
+			"disk": schema.StringAttribute{
+				MarkdownDescription: "Device path of the disk to format, for example `/dev/sdb`. The [`casaos_disks`](../data-sources/disks.md) data source lists the disks of the device. Changing this formats the new disk. " +
+					"A disk the device renames, for example after a reboot, is not noticed: the storage stays in place and keeps the path it was created with.",
+				Required: true,
+				Validators: []validator.String{
+					devicePathValidator,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the storage shown on the dashboard. The storage is mounted at `/media/<name>`. Changing this formats the disk again.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.NoneOf(".", ".."),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^/]+$`), "must not contain slashes"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"filesystem": schema.StringAttribute{
+				MarkdownDescription: "Filesystem to format the disk with: `ext4`, the default, `xfs` or `btrfs`. Changing this formats the disk again.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("ext4"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("ext4", "xfs", "btrfs"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"allow_format": schema.BoolAttribute{
+				MarkdownDescription: "Must be `true`, to acknowledge that creating the storage erases everything on `disk`.",
+				Required:            true,
+			},
+			"mount_point": schema.StringAttribute{
+				MarkdownDescription: "Where the storage is mounted, for example `/media/Data`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"partition": schema.StringAttribute{
+				MarkdownDescription: "Device path of the partition created on the disk, for example `/dev/sdb1`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"size": schema.Int64Attribute{
+				MarkdownDescription: "Capacity of the storage in bytes.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Storage identifier, same as `mount_point`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *StorageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *StorageResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data StorageResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.AllowFormat.IsUnknown() {
+		return
+	}
+
+	if !data.AllowFormat.ValueBool() {
+		addFormatNotAllowedError(&resp.Diagnostics)
+	}
+}
+
+// ModifyPlan checks the disk before it is formatted: it must exist and must
+// not hold the operating system, and any partitions on it are about to be
+// erased.
+func (r *StorageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is formatted when the resource is destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan StorageResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.Disk.IsUnknown() {
+		return
+	}
+
+	// Only a new or replaced storage formats the disk.
+	if !req.State.Raw.IsNull() {
+		var state StorageResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if plan.Disk.Equal(state.Disk) && plan.Name.Equal(state.Name) && plan.Filesystem.Equal(state.Filesystem) {
+			return
+		}
+	}
+
+	// An allow_format unknown during validation is checked once known.
+	if !plan.AllowFormat.IsUnknown() && !plan.AllowFormat.ValueBool() {
+		addFormatNotAllowedError(&resp.Diagnostics)
+		return
+	}
+
+	// The provider is not configured yet when validating without a device.
+	if r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.checkDisk(ctx, plan.Disk.ValueString())...)
+}
+
+func (r *StorageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data StorageResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultStorageCreateTimeout)
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// allow_format may only have become known during apply.
+	if !data.AllowFormat.ValueBool() {
+		addFormatNotAllowedError(&resp.Diagnostics)
+		return
+	}
+
+	devicePath := data.Disk.ValueString()
+
+	// The plan skips these checks when the disk is only known at apply.
+	resp.Diagnostics.Append(r.checkDisk(ctx, devicePath)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.CreateStorage(ctx, devicePath, data.Name.ValueString(), data.Filesystem.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to format disk %q, got error: %s", devicePath, err))
+		return
+	}
+
+	tflog.Info(ctx, "formatting disk", map[string]interface{}{
+		"disk":       devicePath,
+		"filesystem": data.Filesystem.ValueString(),
+	})
+
+	resp.Diagnostics.Append(waitForOperation(ctx, createTimeout, operation{
+		Description: fmt.Sprintf("format of disk %q", devicePath),
+		Poll: func(ctx context.Context) (string, bool, error) {
+			disk, err := r.client.DiskByPath(ctx, devicePath)
+			if err != nil {
+				return "", false, err
+			}
+
+			if disk.State == "" {
+				return "formatted", true, nil
+			}
+
+			return disk.State, false, nil
+		},
+	})...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	storage, err := r.storageOnDisk(ctx, devicePath)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Disk %q was formatted but its storage could not be read, got error: %s", devicePath, err))
+		return
+	}
+
+	data.setStorage(storage)
+
+	tflog.Trace(ctx, "created a storage", map[string]interface{}{
+		"disk":        devicePath,
+		"mount_point": storage.MountPoint,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StorageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data StorageResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	storage, err := r.client.StorageByMountPoint(ctx, data.Id.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "storage no longer exists, removing from state", map[string]interface{}{
+			"mount_point": data.Id.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read storage %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+
+	data.setStorage(storage)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StorageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data StorageResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every other attribute replaces the storage, only allow_format and the
+	// timeouts change in place and neither touches the device.
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StorageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data StorageResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UnmountStorage(ctx, data.Id.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to unmount storage %q, got error: %s", data.Id.ValueString(), err))
+		return
+	}
+}
+
+func (r *StorageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// storageOnDisk returns the storage mounted from a partition of the disk at
+// devicePath.
+func (r *StorageResource) storageOnDisk(ctx context.Context, devicePath string) (*Storage, error) {
+	storages, err := r.client.ListStorages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storage := range storages {
+		if storage.Disk == devicePath {
+			return &storage, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no storage is mounted from disk %q", devicePath)
+}
+
+// setStorage stores storage in m. The disk and the name keep the values they
+// were created with and are only taken from the device after an import: the
+// device path of a disk changes when the disks are renumbered, for example
+// after a reboot, and replacing the storage then would format another disk.
+func (m *StorageResourceModel) setStorage(storage *Storage) {
+	if m.Disk.IsNull() || m.Disk.IsUnknown() {
+		m.Disk = types.StringValue(storage.Disk)
+	}
+
+	if m.Name.IsNull() || m.Name.IsUnknown() {
+		m.Name = types.StringValue(storage.Name)
+	}
+
+	m.Filesystem = types.StringValue(storage.Type)
+	m.Id = types.StringValue(storage.MountPoint)
+	m.MountPoint = types.StringValue(storage.MountPoint)
+	m.Partition = types.StringValue(storage.Path)
+	m.Size = types.Int64Value(storage.Size)
+}
+
+// checkDisk checks the disk at devicePath before it is formatted: it must
+// exist and must not hold the operating system. Partitions on it, which
+// formatting erases, are reported with a warning.
+func (r *StorageResource) checkDisk(ctx context.Context, devicePath string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	disk, err := r.client.DiskByPath(ctx, devicePath)
+	if IsNotFound(err) {
+		diags.AddAttributeError(path.Root("disk"), "Disk Not Found", fmt.Sprintf("Disk %q is not attached to the device.", devicePath))
+		return diags
+	}
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read disk %q, got error: %s", devicePath, err))
+		return diags
+	}
+
+	if len(disk.Children) == 0 {
+		return diags
+	}
+
+	partitions := make([]string, 0, len(disk.Children))
+
+	for _, partition := range disk.Children {
+		if partition.MountPoint == "/" {
+			diags.AddAttributeError(
+				path.Root("disk"),
+				"System Disk",
+				fmt.Sprintf("Disk %q holds the operating system of the device and cannot be formatted.", disk.Path),
+			)
+
+			return diags
+		}
+
+		description := fmt.Sprintf("%s (%s", partition.Path, partition.FSType)
+
+		if partition.MountPoint != "" {
+			description += ", mounted at " + partition.MountPoint
+		}
+
+		partitions = append(partitions, description+")")
+	}
+
+	diags.AddAttributeWarning(
+		path.Root("disk"),
+		"Disk Will Be Erased",
+		fmt.Sprintf("Disk %q (%s, serial %s) has partitions that creating the storage erases:\n  %s",
+			disk.Path, disk.Model, disk.Serial, strings.Join(partitions, "\n  ")),
+	)
+
+	return diags
+}
+
+// addFormatNotAllowedError reports that a storage is created without
+// allow_format set to true.
+func addFormatNotAllowedError(diags *diag.Diagnostics) {
+	diags.AddAttributeError(
+		path.Root("allow_format"),
+		"Formatting Not Allowed",
+		"Creating a storage erases the whole disk. Set allow_format to true to acknowledge this.",
+	)
+}
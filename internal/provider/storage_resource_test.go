@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+// The tests that format a disk only run against the mock CasaOS.
+
+func TestAccStorageResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccStorageResourceConfig("/dev/sdc", "tf-acc-data", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_storage.test", "disk", "/dev/sdc"),
+					resource.TestCheckResourceAttr("casaos_storage.test", "name", "tf-acc-data"),
+					resource.TestCheckResourceAttr("casaos_storage.test", "filesystem", "ext4"),
+					resource.TestCheckResourceAttr("casaos_storage.test", "mount_point", "/media/tf-acc-data"),
+					resource.TestCheckResourceAttr("casaos_storage.test", "partition", "/dev/sdc1"),
+					resource.TestCheckResourceAttr("casaos_storage.test", "size", "2147483648000"),
+					resource.TestCheckResourceAttr("casaos_storage.test", "id", "/media/tf-acc-data"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "casaos_storage.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"allow_format"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccStorageResourceConfig("/dev/sdc", "tf-acc-media", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_storage.test", "name", "tf-acc-media"),
+					resource.TestCheckResourceAttr("casaos_storage.test", "mount_point", "/media/tf-acc-media"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccStorageResource_AllowFormat(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccStorageResourceConfig("/dev/sdc", "tf-acc-data", false),
+				ExpectError: regexp.MustCompile(`Formatting Not Allowed`),
+			},
+		},
+	})
+}
+
+func TestAccStorageResource_AllowFormatUnknown(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if n := mock.Requests(http.MethodPost, "/v1/storage"); n != 0 {
+				return fmt.Errorf("expected the disk not to be formatted, got %d requests", n)
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// allow_format is only known, and false, during apply
+			{
+				Config: `
+resource "casaos_folder" "test" {
+  path = "/DATA/tf-acc-allow-format"
+}
+
+resource "casaos_storage" "test" {
+  disk         = "/dev/sdc"
+  name         = "tf-acc-data"
+  allow_format = casaos_folder.test.id == "/DATA/other"
+}
+`,
+				ExpectError: regexp.MustCompile(`Formatting Not Allowed`),
+			},
+		},
+	})
+}
+
+func TestAccStorageResource_DiskUnknown(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if n := mock.Requests(http.MethodPost, "/v1/storage"); n != 0 {
+				return fmt.Errorf("expected the system disk not to be formatted, got %d requests", n)
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// The disk is only known, and the system disk, during apply
+			{
+				Config: `
+resource "casaos_folder" "test" {
+  path = "/DATA/tf-acc-disk"
+}
+
+resource "casaos_storage" "test" {
+  disk         = casaos_folder.test.id == "/DATA/other" ? "/dev/sdc" : "/dev/sda"
+  name         = "tf-acc-data"
+  allow_format = true
+}
+`,
+				ExpectError: regexp.MustCompile(`System Disk`),
+			},
+		},
+	})
+}
+
+func TestAccStorageResource_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccStorageResourceConfig("/dev/sdz", "tf-acc-data", true),
+				ExpectError: regexp.MustCompile(`Disk Not Found`),
+			},
+			{
+				Config:      testAccStorageResourceConfig("/dev/sda", "tf-acc-data", true),
+				ExpectError: regexp.MustCompile(`System Disk`),
+			},
+		},
+	})
+}
+
+func TestAccStorageResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStorageResourceConfig("/dev/sdc", "tf-acc-data", true),
+				Check: func(*terraform.State) error {
+					// The format only finishes after the disk was polled.
+					if n := mock.Requests(http.MethodGet, "/v1/disks"); n < 2 {
+						return fmt.Errorf("expected the disk to be polled while formatting, got %d listings", n)
+					}
+
+					return nil
+				},
+			},
+			// A disk renamed after a reboot keeps its storage
+			{
+				PreConfig: func() {
+					mock.RenameDisk("/dev/sdc", "/dev/sdd")
+				},
+				Config:   testAccStorageResourceConfig("/dev/sdc", "tf-acc-data", true),
+				PlanOnly: true,
+			},
+			// A storage unmounted from the dashboard is created again
+			{
+				PreConfig: func() {
+					mock.RenameDisk("/dev/sdd", "/dev/sdc")
+					mock.UnmountStorage("/media/tf-acc-data")
+				},
+				Config:             testAccStorageResourceConfig("/dev/sdc", "tf-acc-data", true),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccStorageResourceConfig(disk string, name string, allowFormat bool) string {
+	return fmt.Sprintf(`
+resource "casaos_storage" "test" {
+  disk         = %[1]q
+  name         = %[2]q
+  allow_format = %[3]t
+}
+`, disk, name, allowFormat)
+}
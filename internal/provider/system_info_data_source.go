@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SystemInfoDataSource{}
+
+func NewSystemInfoDataSource() datasource.DataSource {
+	return &SystemInfoDataSource{}
+}
+
+// SystemInfoDataSource defines the data source implementation.
+type SystemInfoDataSource struct {
+	client *CasaOSClient
+}
+
+// SystemInfoDataSourceModel describes the data source data model.
+type SystemInfoDataSourceModel struct {
+	Architecture      types.String            `tfsdk:"architecture"`
+	CasaOSVersion     types.String            `tfsdk:"casaos_version"`
+	CPUCores          types.Int64             `tfsdk:"cpu_cores"`
+	CPUModel          types.String            `tfsdk:"cpu_model"`
+	Hostname          types.String            `tfsdk:"hostname"`
+	Kernel            types.String            `tfsdk:"kernel"`
+	MemoryTotal       types.Int64             `tfsdk:"memory_total"`
+	MemoryUsed        types.Int64             `tfsdk:"memory_used"`
+	NetworkInterfaces []networkInterfaceModel `tfsdk:"network_interfaces"`
+	Uptime            types.Int64             `tfsdk:"uptime"`
+}
+
+// networkInterfaceModel describes a network interface of the device.
+type networkInterfaceModel struct {
+	IPs  []types.String `tfsdk:"ips"`
+	Name types.String   `tfsdk:"name"`
+}
+
+func (d *SystemInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_system_info"
+}
+
+func (d *SystemInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Reads hardware and operating system facts of the device, for example to pick images for its architecture or to size memory limits of apps.",
+
+		Attributes: map[string]schema.Attribute{
+			"casaos_version": schema.StringAttribute{
+				MarkdownDescription: "Version of CasaOS running on the device, for example `0.4.15`.",
+				Computed:            true,
+			},
+			"kernel": schema.StringAttribute{
+				MarkdownDescription: "Version of the Linux kernel.",
+				Computed:            true,
+			},
+			"architecture": schema.StringAttribute{
+				MarkdownDescription: "CPU architecture as Go and Docker name it, for example `amd64` or `arm64`.",
+				Computed:            true,
+			},
+			"hostname": schema.StringAttribute{
+				MarkdownDescription: "Host name of the device.",
+				Computed:            true,
+			},
+			"cpu_model": schema.StringAttribute{
+				MarkdownDescription: "Model name of the CPU.",
+				Computed:            true,
+			},
+			"cpu_cores": schema.Int64Attribute{
+				MarkdownDescription: "Number of CPU cores.",
+				Computed:            true,
+			},
+			"memory_total": schema.Int64Attribute{
+				MarkdownDescription: "Total memory in bytes.",
+				Computed:            true,
+			},
+			"memory_used": schema.Int64Attribute{
+				MarkdownDescription: "Memory in use when the data source was read, in bytes.",
+				Computed:            true,
+			},
+			"uptime": schema.Int64Attribute{
+				MarkdownDescription: "Seconds since the device booted.",
+				Computed:            true,
+			},
+			"network_interfaces": schema.ListNestedAttribute{
+				MarkdownDescription: "Network interfaces of the device, in the order the device reports them.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the interface, for example `eth0`.",
+							Computed:            true,
+						},
+						"ips": schema.ListAttribute{
+							MarkdownDescription: "IPv4 and IPv6 addresses of the interface in CIDR notation, for example `192.168.1.20/24`.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SystemInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *SystemInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SystemInfoDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, err := d.client.SystemVersion(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read CasaOS version, got error: %s", err))
+		return
+	}
+
+	hardware, err := d.client.SystemHardware(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read hardware information, got error: %s", err))
+		return
+	}
+
+	utilization, err := d.client.SystemUtilization(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read system utilization, got error: %s", err))
+		return
+	}
+
+	data.CasaOSVersion = types.StringValue(version.CurrentVersion)
+	data.Kernel = types.StringValue(hardware.Kernel)
+	data.Architecture = types.StringValue(hardware.Arch)
+	data.Hostname = types.StringValue(hardware.Hostname)
+	data.Uptime = types.Int64Value(hardware.Uptime)
+	data.CPUModel = types.StringValue(utilization.CPU.Model)
+	data.CPUCores = types.Int64Value(utilization.CPU.Num)
+	data.MemoryTotal = types.Int64Value(utilization.Mem.Total)
+	data.MemoryUsed = types.Int64Value(utilization.Mem.Used)
+	data.NetworkInterfaces = []networkInterfaceModel{}
+
+	for _, iface := range utilization.Net {
+		model := networkInterfaceModel{
+			IPs:  []types.String{},
+			Name: types.StringValue(iface.Name),
+		}
+
+		for _, ip := range iface.IPs {
+			model.IPs = append(model.IPs, types.StringValue(ip))
+		}
+
+		data.NetworkInterfaces = append(data.NetworkInterfaces, model)
+	}
+
+	tflog.Trace(ctx, "read system info", map[string]interface{}{
+		"casaos_version": version.CurrentVersion,
+		"architecture":   hardware.Arch,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSystemInfoDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccSystemInfoDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("data.casaos_system_info.test", "casaos_version", regexp.MustCompile(`^\d+\.\d+\.\d+`)),
+					resource.TestCheckResourceAttrSet("data.casaos_system_info.test", "kernel"),
+					resource.TestMatchResourceAttr("data.casaos_system_info.test", "architecture", regexp.MustCompile(`^(amd64|arm64|arm)`)),
+					resource.TestCheckResourceAttrSet("data.casaos_system_info.test", "hostname"),
+					resource.TestCheckResourceAttrSet("data.casaos_system_info.test", "cpu_model"),
+					resource.TestMatchResourceAttr("data.casaos_system_info.test", "cpu_cores", regexp.MustCompile(`^[1-9]\d*$`)),
+					resource.TestMatchResourceAttr("data.casaos_system_info.test", "memory_total", regexp.MustCompile(`^[1-9]\d*$`)),
+					resource.TestCheckResourceAttrSet("data.casaos_system_info.test", "memory_used"),
+					resource.TestCheckResourceAttrSet("data.casaos_system_info.test", "uptime"),
+					resource.TestCheckResourceAttrSet("data.casaos_system_info.test", "network_interfaces.0.name"),
+				),
+			},
+		},
+	})
+}
+
+const testAccSystemInfoDataSourceConfig = `
+data "casaos_system_info" "test" {}
+`
@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &UserCustomDataResource{}
+var _ resource.ResourceWithImportState = &UserCustomDataResource{}
+var _ resource.ResourceWithValidateConfig = &UserCustomDataResource{}
+
+func NewUserCustomDataResource() resource.Resource {
+	return &UserCustomDataResource{}
+}
+
+// UserCustomDataResource defines the resource implementation.
+type UserCustomDataResource struct {
+	client *CasaOSClient
+}
+
+// UserCustomDataResourceModel describes the resource data model.
+type UserCustomDataResourceModel struct {
+	Id    types.String         `tfsdk:"id"`
+	Key   types.String         `tfsdk:"key"`
+	Value jsontypes.Normalized `tfsdk:"value"`
+}
+
+// managedCustomDataKeys are the custom data keys other resources of the
+// provider manage.
+var managedCustomDataKeys = map[string]string{
+	appOrderKey:       "casaos_app_grid",
+	appHiddenKey:      "casaos_app_grid",
+	appLinksKey:       "casaos_app_link",
+	widgetSettingsKey: "casaos_dashboard_widgets",
+}
+
+func (r *UserCustomDataResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_custom_data"
+}
+
+func (r *UserCustomDataResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages a JSON document in the custom data of the logged in user, where the dashboard keeps preferences such as the language, the wallpaper and the search engine. " +
+			"The keys and documents the dashboard uses depend on the CasaOS version, import an existing key to see what the dashboard stored.\n\n" +
+			"Documents are compared by their JSON value, so CasaOS storing a document with different formatting or object key order does not show up as a change.",
+
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "Key the document is stored under, for example `wallpaper`. Changing this moves the document to the new key.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9_.-]+$`), "must only contain letters, digits, dots, dashes and underscores"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "JSON document to store, usually written with `jsonencode()`.",
+				CustomType:          jsontypes.NormalizedType{},
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Custom data identifier, same as `key`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *UserCustomDataResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *UserCustomDataResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data UserCustomDataResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if owner, ok := managedCustomDataKeys[data.Key.ValueString()]; ok {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("key"),
+			"Key Managed by Another Resource",
+			fmt.Sprintf("The %s resource manages custom data key %q. Managing it here as well makes the two resources overwrite each other.", owner, data.Key.ValueString()),
+		)
+	}
+}
+
+func (r *UserCustomDataResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data UserCustomDataResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetCustomData(ctx, data.Key.ValueString(), json.RawMessage(data.Value.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to store custom data %q, got error: %s", data.Key.ValueString(), err))
+		return
+	}
+
+	data.Id = data.Key
+
+	tflog.Trace(ctx, "stored custom data", map[string]interface{}{
+		"key": data.Key.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserCustomDataResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data UserCustomDataResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	value, err := r.client.CustomData(ctx, data.Key.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "custom data no longer exists, removing from state", map[string]interface{}{
+			"key": data.Key.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read custom data %q, got error: %s", data.Key.ValueString(), err))
+		return
+	}
+
+	// The framework keeps the prior value when the new one is semantically
+	// equal, so reformatting by CasaOS does not show up as a change.
+	data.Id = data.Key
+	data.Value = jsontypes.NewNormalizedValue(string(value))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserCustomDataResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data UserCustomDataResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetCustomData(ctx, data.Key.ValueString(), json.RawMessage(data.Value.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to store custom data %q, got error: %s", data.Key.ValueString(), err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserCustomDataResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data UserCustomDataResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteCustomData(ctx, data.Key.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete custom data %q, got error: %s", data.Key.ValueString(), err))
+		return
+	}
+}
+
+func (r *UserCustomDataResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+func TestAccUserCustomDataResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccUserCustomDataResourceConfig("tf_test", `jsonencode({ lang = "en_us", search = { engine = "duckduckgo" } })`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_user_custom_data.test", "key", "tf_test"),
+					resource.TestCheckResourceAttr("casaos_user_custom_data.test", "value", `{"lang":"en_us","search":{"engine":"duckduckgo"}}`),
+					resource.TestCheckResourceAttr("casaos_user_custom_data.test", "id", "tf_test"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "casaos_user_custom_data.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccUserCustomDataResourceConfig("tf_test", `jsonencode({ lang = "de_de", search = { engine = "duckduckgo" } })`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_user_custom_data.test", "value", `{"lang":"de_de","search":{"engine":"duckduckgo"}}`),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccUserCustomDataResource_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserCustomDataResourceConfig("tf_test", `"{lang"`),
+				ExpectError: regexp.MustCompile(`Invalid JSON String Value`),
+			},
+			{
+				Config:      testAccUserCustomDataResourceConfig("tf/test", `jsonencode({})`),
+				ExpectError: regexp.MustCompile(`must only contain letters, digits, dots, dashes and\s+underscores`),
+			},
+		},
+	})
+}
+
+func TestAccUserCustomDataResource_Drift(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserCustomDataResourceConfig("wallpaper", `jsonencode({ url = "/wallpaper.jpg", from = "Built-in" })`),
+			},
+			// A document the dashboard stores reformatted is not a change
+			{
+				PreConfig: func() {
+					mock.SetCustomData("wallpaper", "{\n  \"url\": \"/wallpaper.jpg\",\n  \"from\": \"Built-in\"\n}")
+				},
+				Config:   testAccUserCustomDataResourceConfig("wallpaper", `jsonencode({ url = "/wallpaper.jpg", from = "Built-in" })`),
+				PlanOnly: true,
+			},
+			// but one changed on the dashboard is changed back
+			{
+				PreConfig: func() {
+					mock.SetCustomData("wallpaper", `{"url":"/upload/beach.jpg","from":"Upload"}`)
+				},
+				Config:             testAccUserCustomDataResourceConfig("wallpaper", `jsonencode({ url = "/wallpaper.jpg", from = "Built-in" })`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccUserCustomDataResource_ManagedKey(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Managing a key of another resource warns but works
+			{
+				Config: testAccUserCustomDataResourceConfig("app_hidden", `jsonencode([])`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_user_custom_data.test", "value", `[]`),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserCustomDataResourceConfig(key string, value string) string {
+	return fmt.Sprintf(`
+resource "casaos_user_custom_data" "test" {
+  key   = %[1]q
+  value = %[2]s
+}
+`, key, value)
+}
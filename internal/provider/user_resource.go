@@ -0,0 +1,299 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &UserResource{}
+var _ resource.ResourceWithImportState = &UserResource{}
+
+func NewUserResource() resource.Resource {
+	return &UserResource{}
+}
+
+// UserResource defines the resource implementation.
+type UserResource struct {
+	client *CasaOSClient
+}
+
+// UserResourceModel describes the resource data model.
+type UserResourceModel struct {
+	Avatar   types.String `tfsdk:"avatar"`
+	Id       types.String `tfsdk:"id"`
+	Password types.String `tfsdk:"password"`
+	Username types.String `tfsdk:"username"`
+}
+
+func (r *UserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages the owner account of the device. " +
+			"On a freshly installed device that has no account yet, creating the resource registers the owner, so a new device can be set up without the first-run wizard. " +
+			"On a device that has an owner, creating the resource takes over the account the provider logs in as.\n\n" +
+			"To change the credentials the provider logs in with, change them here and apply, then change them in the provider configuration. " +
+			"The provider fails to log in until its configuration is updated.\n\n" +
+			"An imported account must be the one the provider logs in as.\n\n" +
+			"CasaOS cannot delete its owner, destroying the resource only removes it from the state.",
+
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Name of the account.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password of the account, at least 5 characters. CasaOS does not return it, so a password changed outside Terraform is only noticed when the resource can no longer log in.",
+				Required:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(5),
+				},
+			},
+			"avatar": schema.StringAttribute{
+				MarkdownDescription: "URL of the picture shown for the account on the dashboard. Left as it is on the device when not set.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID of the account.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *UserResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*CasaOSClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CasaOSClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data UserResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := r.client.UserStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to check whether the device has an owner, got error: %s", err))
+		return
+	}
+
+	if !status.Initialized {
+		err := r.client.RegisterOwner(ctx, data.Username.ValueString(), data.Password.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to register owner %q, got error: %s", data.Username.ValueString(), err))
+			return
+		}
+
+		tflog.Info(ctx, "registered the owner of the device", map[string]interface{}{
+			"username": data.Username.ValueString(),
+		})
+	}
+
+	// Converge the account the provider logs in as, which is the new owner
+	// after registering it.
+	resp.Diagnostics.Append(r.converge(ctx, r.client, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	user, err := r.session(data).CurrentUser(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read user %q, got error: %s", data.Username.ValueString(), err))
+		return
+	}
+
+	// Without a password in state, as after an import, the session is the
+	// provider's, which can only read the account it logs in as.
+	if data.Password.IsNull() && user.Username != data.Username.ValueString() {
+		resp.Diagnostics.AddError(
+			"Unexpected User",
+			fmt.Sprintf("The provider logs in as %q, not as the imported user %q. "+
+				"CasaOS only lets an account manage itself, import the account the provider logs in as.", user.Username, data.Username.ValueString()),
+		)
+
+		return
+	}
+
+	// The password is not returned and stays as it is in state.
+	data.setUser(user)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state UserResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.converge(ctx, r.session(state), &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Owner Account Kept",
+		fmt.Sprintf("CasaOS cannot delete its owner, so account %q stays on the device. It is only removed from the Terraform state.", data.Username.ValueString()),
+	)
+}
+
+func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("username"), req, resp)
+}
+
+// session returns a client logged in with the credentials in state. Without
+// a password in state, as after an import, it returns the provider's client.
+func (r *UserResource) session(state UserResourceModel) *CasaOSClient {
+	username, password := r.client.Credentials()
+
+	if state.Password.IsNull() || (state.Username.ValueString() == username && state.Password.ValueString() == password) {
+		return r.client
+	}
+
+	return r.client.WithCredentials(state.Username.ValueString(), state.Password.ValueString())
+}
+
+// converge changes the account session is logged in as to match data, and
+// stores the result in data. When the provider logs in as the same account,
+// it uses the new credentials from then on.
+func (r *UserResource) converge(ctx context.Context, session *CasaOSClient, data *UserResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	user, err := session.CurrentUser(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read the current user, got error: %s", err))
+		return diags
+	}
+
+	oldUsername := user.Username
+	providerUsername, _ := r.client.Credentials()
+
+	var update UserUpdate
+
+	if username := data.Username.ValueString(); username != user.Username {
+		update.Username = &username
+	}
+
+	if avatar := data.Avatar.ValueString(); !data.Avatar.IsUnknown() && avatar != user.Avatar {
+		update.Avatar = &avatar
+	}
+
+	if update.Username != nil || update.Avatar != nil {
+		user, err = session.UpdateCurrentUser(ctx, update)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to update user %q, got error: %s", oldUsername, err))
+			return diags
+		}
+	}
+
+	if _, password := session.Credentials(); password != data.Password.ValueString() {
+		if err := session.ChangePassword(ctx, data.Password.ValueString()); err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to change the password of user %q, got error: %s", user.Username, err))
+			return diags
+		}
+	}
+
+	if session != r.client && providerUsername == oldUsername {
+		r.client.SetCredentials(data.Username.ValueString(), data.Password.ValueString())
+	}
+
+	tflog.Trace(ctx, "updated the owner account", map[string]interface{}{
+		"username": user.Username,
+	})
+
+	data.setUser(user)
+
+	return diags
+}
+
+func (m *UserResourceModel) setUser(user *User) {
+	m.Id = types.StringValue(strconv.FormatInt(user.ID, 10))
+	m.Username = types.StringValue(user.Username)
+	m.Avatar = types.StringValue(user.Avatar)
+}
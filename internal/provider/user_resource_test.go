@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/casaosmock"
+)
+
+// The user tests change the account the provider logs in with, so they only
+// run against the mock CasaOS.
+
+func TestAccUserResource_FirstBoot(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			mock = testAccMockOnly(t)
+			mock.RemoveUsers()
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccUserResourceConfig(casaosmock.Username, casaosmock.Password, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_user.test", "id", "1"),
+					resource.TestCheckResourceAttr("casaos_user.test", "username", casaosmock.Username),
+					resource.TestCheckResourceAttr("casaos_user.test", "avatar", ""),
+					func(*terraform.State) error {
+						if n := mock.Requests(http.MethodPost, "/v1/users/register"); n != 1 {
+							return fmt.Errorf("expected the owner to be registered once, got %d registrations", n)
+						}
+
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "casaos_user.test",
+				ImportState:                          true,
+				ImportStateId:                        casaosmock.Username,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "username",
+				ImportStateVerifyIgnore:              []string{"password"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccUserResourceConfig(casaosmock.Username, casaosmock.Password, "https://example.com/avatar.png"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_user.test", "avatar", "https://example.com/avatar.png"),
+				),
+			},
+			// Changing the credentials the provider logs in with, which
+			// fails to log in until its configuration follows
+			{
+				Config:      testAccUserResourceConfig("owner", "new-secret", "https://example.com/avatar.png"),
+				ExpectError: regexp.MustCompile(`Unable to Log In to CasaOS`),
+			},
+			{
+				Config: testAccUserResourceProviderConfig("owner", "new-secret") +
+					testAccUserResourceConfig("owner", "new-secret", "https://example.com/avatar.png"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_user.test", "username", "owner"),
+					resource.TestCheckResourceAttr("casaos_user.test", "password", "new-secret"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccUserResource_Adopt(t *testing.T) {
+	var mock *casaosmock.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { mock = testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceConfig(casaosmock.Username, casaosmock.Password, "https://example.com/avatar.png"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("casaos_user.test", "id", "1"),
+					resource.TestCheckResourceAttr("casaos_user.test", "avatar", "https://example.com/avatar.png"),
+					func(*terraform.State) error {
+						if n := mock.Requests(http.MethodPost, "/v1/users/register"); n != 0 {
+							return fmt.Errorf("expected the existing owner to be taken over, got %d registrations", n)
+						}
+
+						return nil
+					},
+				),
+			},
+			// An avatar changed on the dashboard is set back
+			{
+				PreConfig: func() {
+					mock.SetAvatar("https://example.com/other.png")
+				},
+				Config:             testAccUserResourceConfig(casaosmock.Username, casaosmock.Password, "https://example.com/avatar.png"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResource_ImportOther(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccMockOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccUserResourceConfig("someone", "secret", ""),
+				ResourceName:  "casaos_user.test",
+				ImportState:   true,
+				ImportStateId: "someone",
+				ExpectError:   regexp.MustCompile(`Unexpected User`),
+			},
+		},
+	})
+}
+
+func testAccUserResourceProviderConfig(username string, password string) string {
+	return fmt.Sprintf(`
+provider "casaos" {
+  username = %[1]q
+  password = %[2]q
+}
+`, username, password)
+}
+
+func testAccUserResourceConfig(username string, password string, avatar string) string {
+	return fmt.Sprintf(`
+resource "casaos_user" "test" {
+  username = %[1]q
+  password = %[2]q
+  avatar   = %[3]q
+}
+`, username, password, avatar)
+}